@@ -35,3 +35,6 @@ LD_LIBRARY_PATH=$PREFIX/lib
 CGO_LDFLAGS=$(mecab-config --libs)
 CGO_CFLAGS=-I$(mecab-config --inc-dir)
 CGO_FLAGS
+
+# mecab-cost-train is used to report the progress of the training.
+mecab-config --libexecdir >> "$GITHUB_PATH"
@@ -44,3 +44,5 @@ DIC
 echo "MECABRC_PATH=$(cygpath -w "$PREFIX/etc/mecabrc")" >> "$GITHUB_ENV"
 cygpath -w /mingw64/bin >> "$GITHUB_PATH"
 cygpath -w "$PREFIX/bin" >> "$GITHUB_PATH"
+# mecab-cost-train is used to report the progress of the training.
+cygpath -w "$PREFIX/libexec/mecab" >> "$GITHUB_PATH"
@@ -0,0 +1,90 @@
+package mecab
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// iconvCharset returns the name of charset for iconv.
+// It returns an empty string if charset is UTF-8.
+func iconvCharset(charset string) string {
+	name := strings.ToLower(charset)
+	name = strings.NewReplacer("-", "", "_", "").Replace(name)
+	switch name {
+	case "", "utf8":
+		return ""
+	case "eucjp":
+		return "EUC-JP"
+	case "sjis", "shiftjis":
+		return "SHIFT_JIS"
+	case "cp932", "windows31j", "ms932":
+		return "CP932"
+	}
+	return charset
+}
+
+// codec converts strings between UTF-8 and the charset of the dictionary.
+type codec struct {
+	charset string
+	enc     *converter // UTF-8 to charset
+	dec     *converter // charset to UTF-8
+}
+
+// newCodec returns the codec for charset.
+// It returns nil if charset is UTF-8.
+func newCodec(op, charset string) (*codec, error) {
+	name := iconvCharset(charset)
+	if name == "" {
+		return nil, nil
+	}
+	enc := newConverter(name, "UTF-8")
+	dec := newConverter("UTF-8", name)
+	if enc == nil || dec == nil {
+		return nil, &Error{
+			Op:   op,
+			Kind: KindCharsetMismatch,
+			err:  "unsupported charset: " + charset,
+		}
+	}
+	return &codec{
+		charset: name,
+		enc:     enc,
+		dec:     dec,
+	}, nil
+}
+
+// encode converts s from UTF-8 to the charset of the dictionary.
+func (c *codec) encode(op, s string) (string, error) {
+	ret, offset, ok := c.enc.convert(s, false, "")
+	if !ok {
+		r, _ := utf8.DecodeRuneInString(s[offset:])
+		return "", &Error{
+			Op:   op,
+			Kind: KindInvalidInput,
+			err:  "cannot convert " + strconv.QuoteRune(r) + " to " + c.charset,
+		}
+	}
+	return ret, nil
+}
+
+// decode converts s from the charset of the dictionary to UTF-8.
+// Invalid sequences are replaced with U+FFFD.
+func (c *codec) decode(s string) string {
+	ret, _, _ := c.dec.convert(s, true, string(utf8.RuneError))
+	return ret
+}
+
+// initCodec sets up the codec for the charset of the system dictionary.
+func (m *mecab) initCodec(op string) error {
+	info := MeCab{m: m}.DictionaryInfo()
+	if len(info) == 0 {
+		return nil
+	}
+	c, err := newCodec(op, info[0].Charset)
+	if err != nil {
+		return err
+	}
+	m.codec = c
+	return nil
+}
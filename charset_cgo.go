@@ -0,0 +1,103 @@
+//go:build cgo && !purego
+
+package mecab
+
+// #cgo darwin LDFLAGS: -liconv
+// #cgo windows LDFLAGS: -liconv
+// #include <errno.h>
+// #include <mecab.h>
+// #include <iconv.h>
+// #include <stdlib.h>
+//
+// static iconv_t mecab_iconv_open(const char *to, const char *from) {
+//   iconv_t cd = iconv_open(to, from);
+//   return cd == (iconv_t)-1 ? NULL : cd;
+// }
+//
+// static size_t mecab_iconv(iconv_t cd, char *in, size_t *inleft, char *out, size_t *outleft) {
+//   return iconv(cd, in == NULL ? NULL : &in, inleft, &out, outleft);
+// }
+import "C"
+
+import (
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// converter is a wrapper of iconv.
+type converter struct {
+	mu sync.Mutex
+	cd C.iconv_t
+}
+
+func newConverter(to, from string) *converter {
+	cto := C.CString(to)
+	defer C.free(unsafe.Pointer(cto))
+	cfrom := C.CString(from)
+	defer C.free(unsafe.Pointer(cfrom))
+
+	cd := C.mecab_iconv_open(cto, cfrom)
+	if cd == nil {
+		return nil
+	}
+	c := &converter{cd: cd}
+	runtime.SetFinalizer(c, finalizeConverter)
+	return c
+}
+
+func finalizeConverter(c *converter) {
+	C.iconv_close(c.cd)
+}
+
+// convert converts s. If lenient is true, invalid sequences are replaced with replacement.
+// Otherwise it returns the offset of the invalid sequence.
+func (c *converter) convert(s string, lenient bool, replacement string) (string, int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer runtime.KeepAlive(c)
+
+	// reset the state
+	C.mecab_iconv(c.cd, nil, nil, nil, nil)
+	if s == "" {
+		return "", 0, true
+	}
+
+	in := []byte(s)
+	out := make([]byte, len(in)*2+16)
+	var buf []byte
+	i := 0
+	for i < len(in) {
+		inleft := C.size_t(len(in) - i)
+		outleft := C.size_t(len(out))
+		r, err := C.mecab_iconv(
+			c.cd,
+			(*C.char)(unsafe.Pointer(&in[i])), &inleft,
+			(*C.char)(unsafe.Pointer(&out[0])), &outleft,
+		)
+		consumed := len(in) - i - int(inleft)
+		buf = append(buf, out[:len(out)-int(outleft)]...)
+		i += consumed
+		if r != ^C.size_t(0) {
+			break
+		}
+		switch err {
+		case syscall.E2BIG:
+			// the output buffer is full, continue.
+		case syscall.EILSEQ, syscall.EINVAL:
+			if !lenient {
+				return "", i, false
+			}
+			buf = append(buf, replacement...)
+			i++
+		default:
+			if !lenient {
+				return "", i, false
+			}
+			buf = append(buf, replacement...)
+			i = len(in)
+		}
+	}
+	return string(buf), 0, true
+}
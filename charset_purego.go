@@ -0,0 +1,15 @@
+//go:build !cgo || purego
+
+package mecab
+
+// converter is not available without iconv,
+// so the pure Go implementation supports only UTF-8.
+type converter struct{}
+
+func newConverter(to, from string) *converter {
+	return nil
+}
+
+func (c *converter) convert(s string, lenient bool, replacement string) (string, int, bool) {
+	return s, 0, true
+}
@@ -0,0 +1,99 @@
+package mecab
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIconvCharset(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"utf8", ""},
+		{"UTF-8", ""},
+		{"euc-jp", "EUC-JP"},
+		{"EUC_JP", "EUC-JP"},
+		{"sjis", "SHIFT_JIS"},
+		{"Shift_JIS", "SHIFT_JIS"},
+		{"cp932", "CP932"},
+	}
+	for _, tt := range tests {
+		if got := iconvCharset(tt.input); got != tt.want {
+			t.Errorf("%q: want %q, got %q", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestCodec(t *testing.T) {
+	tests := []struct {
+		charset string
+		input   string
+		encoded string
+	}{
+		{"euc-jp", "テスト", "\xa5\xc6\xa5\xb9\xa5\xc8"},
+		{"sjis", "テスト", "\x83\x65\x83\x58\x83\x67"},
+		{"euc-jp", "", ""},
+		{"euc-jp", strings.Repeat("テ", 10000), strings.Repeat("\xa5\xc6", 10000)},
+	}
+
+	for _, tt := range tests {
+		c, err := newCodec("New", tt.charset)
+		if err != nil {
+			t.Skipf("%s is not supported: %v", tt.charset, err)
+		}
+		encoded, err := c.encode("Parse", tt.input)
+		if err != nil {
+			t.Errorf("%s, %q: unexpected error: %v", tt.charset, tt.input, err)
+			continue
+		}
+		if encoded != tt.encoded {
+			t.Errorf("%s, %q: unexpected encoded string %q", tt.charset, tt.input, encoded)
+		}
+		if decoded := c.decode(encoded); decoded != tt.input {
+			t.Errorf("%s, %q: unexpected decoded string %q", tt.charset, tt.input, decoded)
+		}
+	}
+}
+
+func TestCodec_error(t *testing.T) {
+	c, err := newCodec("New", "euc-jp")
+	if err != nil {
+		t.Skipf("euc-jp is not supported: %v", err)
+	}
+
+	if _, err := c.encode("Parse", "テスト😀"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("want ErrInvalidInput, got %v", err)
+	}
+	if got := c.decode("\xa5\xc6\xff\xa5\xc8"); got != "テ�ト" {
+		t.Errorf("want %q, got %q", "テ�ト", got)
+	}
+
+	if _, err := newCodec("New", "unknown-charset"); !errors.Is(err, ErrCharsetMismatch) {
+		t.Errorf("want ErrCharsetMismatch, got %v", err)
+	}
+	if c, err := newCodec("New", "utf-8"); c != nil || err != nil {
+		t.Errorf("want nil, got %v, %v", c, err)
+	}
+}
+
+func TestDictionaryInfo(t *testing.T) {
+	mecab, err := New(rcfile(map[string]string{}))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer mecab.Destroy()
+
+	info := mecab.DictionaryInfo()
+	if len(info) == 0 {
+		t.Fatal("want dictionary info, got nothing")
+	}
+	if info[0].Type != SystemDictionary {
+		t.Errorf("want system dictionary, got %s", info[0].Type)
+	}
+	if info[0].Charset == "" {
+		t.Error("want charset, got empty")
+	}
+}
@@ -0,0 +1,310 @@
+package mecab
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/shogo82148/go-mecab/dic"
+)
+
+// CharCategory is a character category defined in char.def, e.g. KANJI, KATAKANA or ALPHA.
+// The categories are used to build the unknown words.
+type CharCategory struct {
+	// ID is the index of the category. It is the value of [Node.CharType].
+	ID int
+
+	// Name is the name of the category.
+	Name string
+
+	// Invoke means that the unknown word processing is invoked even if a known word is found.
+	Invoke bool
+
+	// Group means that the characters of the same category are grouped into an unknown word.
+	Group bool
+
+	// Length is the maximum length in characters of the unknown words,
+	// in addition to the grouped word.
+	Length int
+}
+
+// CharCategories returns the character categories of the system dictionary.
+// The i-th category has the ID i.
+//
+// The settings of the categories are read from char.def in the dictionary directory if it exists.
+// Otherwise they are read from char.bin, where the settings of the categories
+// that are not the default category of any character are unknown.
+func (m Model) CharCategories() ([]CharCategory, error) {
+	if m.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+	categories, _, err := m.m.files.charCategories("CharCategories", m.DictionaryInfo(), 0)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(categories), nil
+}
+
+// CategoryOf returns the character categories of r.
+// The first one is the default category, which decides how the unknown words starting with r are built.
+// The characters out of the BMP have the categories of U+0000, as MeCab does.
+func (m Model) CategoryOf(r rune) ([]CharCategory, error) {
+	if m.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+	categories, info, err := m.m.files.charCategories("CategoryOf", m.DictionaryInfo(), r)
+	if err != nil {
+		return nil, err
+	}
+	var ret []CharCategory
+	if info.DefaultType < len(categories) {
+		ret = append(ret, categories[info.DefaultType])
+	}
+	for i, c := range categories {
+		if i != info.DefaultType && info.IsKindOf(i) {
+			ret = append(ret, c)
+		}
+	}
+	return ret, nil
+}
+
+// CharTypeName returns the name of the character category of the node, e.g. "KANJI".
+// It returns an empty string if the character definitions of the dictionary are not available.
+//
+// The names are loaded when it is called for the first time by the nodes of the tagger,
+// and they are not updated by [Model.Swap].
+func (node Node) CharTypeName() string {
+	i := node.CharType()
+	m := node.tagger()
+	if m == nil {
+		return ""
+	}
+	names := m.names.charTypes(m)
+	if i < 0 || i >= len(names) {
+		return ""
+	}
+	return names[i]
+}
+
+// tagger returns the tagger that parsed the node.
+func (node Node) tagger() *mecab {
+	if node.mecab != nil {
+		return node.mecab
+	}
+	if node.lattice != nil {
+		return node.lattice.tagger
+	}
+	return nil
+}
+
+// nodeNames is the names of the IDs of the nodes, which are loaded by a tagger lazily.
+type nodeNames struct {
+	charOnce  sync.Once
+	charNames []string
+
+	posOnce sync.Once
+	pos     *IDTable
+
+	layoutOnce sync.Once
+	layout     UniDicLayout
+}
+
+// charTypes returns the names of the character categories.
+func (n *nodeNames) charTypes(m *mecab) []string {
+	n.charOnce.Do(func() {
+		dicdir, ok := m.dicdir()
+		if !ok {
+			return
+		}
+		char, err := dic.OpenCharProperty(filepath.Join(dicdir, "char.bin"))
+		if err != nil {
+			return
+		}
+		defer char.Close()
+		n.charNames = slices.Clone(char.Categories())
+	})
+	return n.charNames
+}
+
+// posIDs returns the table of pos-id.def.
+func (n *nodeNames) posIDs(m *mecab) *IDTable {
+	n.posOnce.Do(func() {
+		if m.mecab == nil {
+			return
+		}
+		info := MeCab{m: m}.DictionaryInfo()
+		dicdir, ok := systemDicdir(info)
+		if !ok {
+			return
+		}
+		n.pos, _ = readIDTable("PosName", filepath.Join(dicdir, "pos-id.def"), true, info[0].Charset)
+	})
+	return n.pos
+}
+
+// uniDicLayout returns the layout of UniDic detected from dicrc,
+// or [UniDicLayoutAuto] if it is not available.
+func (n *nodeNames) uniDicLayout(m *mecab) UniDicLayout {
+	n.layoutOnce.Do(func() {
+		dicdir, ok := m.dicdir()
+		if !ok {
+			return
+		}
+		layout, err := DetectUniDicLayout(filepath.Join(dicdir, "dicrc"))
+		if err == nil {
+			n.layout = layout
+		}
+	})
+	return n.layout
+}
+
+// dicdir returns the directory of the system dictionary of the tagger.
+func (m *mecab) dicdir() (string, bool) {
+	if m.mecab == nil {
+		return "", false
+	}
+	return systemDicdir(MeCab{m: m}.DictionaryInfo())
+}
+
+// systemDicdir returns the directory of the system dictionary.
+func systemDicdir(info []DictionaryInfo) (string, bool) {
+	for _, i := range info {
+		if i.Type == SystemDictionary {
+			return filepath.Dir(i.Filename), true
+		}
+	}
+	return "", false
+}
+
+// charCategories returns the character categories and the information of r.
+// The character definitions are reopened if the model is swapped.
+func (c *dictionaryFiles) charCategories(op string, info []DictionaryInfo, r rune) ([]CharCategory, dic.CharInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dicdir, ok := systemDicdir(info)
+	if !ok {
+		return nil, dic.CharInfo{}, &Error{
+			Op:   op,
+			Kind: KindDictionaryNotFound,
+			err:  "no system dictionary",
+		}
+	}
+	if c.char == nil || c.dicdir != dicdir {
+		c.closeCharProperty()
+		name := filepath.Join(dicdir, "char.bin")
+		char, err := dic.OpenCharProperty(name)
+		if err != nil {
+			return nil, dic.CharInfo{}, &Error{
+				Op:   op,
+				Path: name,
+				Kind: KindDictionaryNotFound,
+				err:  err.Error(),
+			}
+		}
+		categories, err := readCharDef(op, filepath.Join(dicdir, "char.def"))
+		if err != nil {
+			char.Close()
+			return nil, dic.CharInfo{}, err
+		}
+		c.dicdir, c.char, c.categories = dicdir, char, mergeCharCategories(char, categories)
+	}
+	return c.categories, c.char.Info(r), nil
+}
+
+func (c *dictionaryFiles) closeCharProperty() {
+	if c.char != nil {
+		c.char.Close()
+	}
+	c.dicdir, c.char, c.categories = "", nil, nil
+}
+
+// mergeCharCategories builds the categories of char.bin with the settings of char.def.
+// The settings of the categories missing in char.def are taken from the characters
+// whose default category is the category.
+func mergeCharCategories(char *dic.CharProperty, defs map[string]CharCategory) []CharCategory {
+	names := char.Categories()
+	categories := make([]CharCategory, len(names))
+	found := make([]bool, len(names))
+	missing := len(names)
+	for i, name := range names {
+		categories[i] = CharCategory{ID: i, Name: name}
+		if def, ok := defs[name]; ok {
+			categories[i].Invoke, categories[i].Group, categories[i].Length = def.Invoke, def.Group, def.Length
+			found[i] = true
+			missing--
+		}
+	}
+	for r := rune(0); r < 0xffff && missing > 0; r++ {
+		info := char.Info(r)
+		if info.DefaultType < len(categories) && !found[info.DefaultType] {
+			c := &categories[info.DefaultType]
+			c.Invoke, c.Group, c.Length = info.Invoke, info.Group, info.Length
+			found[info.DefaultType] = true
+			missing--
+		}
+	}
+	return categories
+}
+
+// readCharDef reads the category definitions of char.def, e.g. "KANJI 0 0 2".
+// It returns nil if the file doesn't exist.
+func readCharDef(op, name string) (map[string]CharCategory, error) {
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ret := map[string]CharCategory{}
+	s := bufio.NewScanner(f)
+	n := 0
+	for s.Scan() {
+		n++
+		line, _, _ := strings.Cut(s.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "0x") {
+			// the mappings from the code points to the categories.
+			continue
+		}
+		if len(fields) < 4 {
+			return nil, defFileError(op, name, n, "want the name, invoke, group and length of the category")
+		}
+		var values [3]int
+		for i, field := range fields[1:4] {
+			v, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, defFileError(op, name, n, "invalid number: "+field)
+			}
+			values[i] = v
+		}
+		ret[fields[0]] = CharCategory{
+			Name:   fields[0],
+			Invoke: values[0] != 0,
+			Group:  values[1] != 0,
+			Length: values[2],
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func defFileError(op, name string, line int, msg string) error {
+	return &Error{
+		Op:   op,
+		Path: name,
+		err:  fmt.Sprintf("%s:%d: %s", name, line, msg),
+	}
+}
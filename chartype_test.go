@@ -0,0 +1,83 @@
+package mecab
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadCharDef(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "char.def")
+	def := "# the categories\n" +
+		"DEFAULT 0 1 0  # DEFAULT is a mandatory category!\n" +
+		"KANJI   0 0 2\n" +
+		"\n" +
+		"0x0020 SPACE\n" +
+		"0x4E00..0x9FFF KANJI\n"
+	if err := os.WriteFile(name, []byte(def), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readCharDef("Test", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 ||
+		got["DEFAULT"] != (CharCategory{Name: "DEFAULT", Group: true}) ||
+		got["KANJI"] != (CharCategory{Name: "KANJI", Length: 2}) {
+		t.Errorf("unexpected categories: %v", got)
+	}
+
+	if err := os.WriteFile(name, []byte("KANJI 0 0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readCharDef("Test", name); err == nil {
+		t.Error("want error, got nil")
+	}
+
+	got, err = readCharDef("Test", filepath.Join(t.TempDir(), "missing.def"))
+	if err != nil || got != nil {
+		t.Errorf("want nil, got %v, %v", got, err)
+	}
+}
+
+func TestModel_CharCategories(t *testing.T) {
+	model, err := NewModel(rcfile(map[string]string{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer model.Destroy()
+
+	categories, err := model.CharCategories()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(categories) == 0 || categories[0].Name != "DEFAULT" {
+		t.Errorf("want DEFAULT first, got %v", categories)
+	}
+	for i, c := range categories {
+		if c.ID != i {
+			t.Errorf("want ID %d, got %v", i, c)
+		}
+	}
+
+	got, err := model.CategoryOf('漢')
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) == 0 || got[0].Name != "KANJI" {
+		t.Errorf("want KANJI, got %v", got)
+	}
+
+	mecab, err := model.NewMeCab()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer mecab.Destroy()
+	node, err := mecab.ParseToNode("ＸＹＺ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name := node.Next().CharTypeName(); name != "ALPHA" {
+		t.Errorf("want ALPHA, got %q", name)
+	}
+}
@@ -0,0 +1,220 @@
+package mecab
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultInputBufferSize is the default value of input-buffer-size of MeCab.
+const DefaultInputBufferSize = 8192
+
+// ChunkMode is the behavior of [Chunker] for long inputs.
+type ChunkMode int
+
+const (
+	// ChunkAuto splits long inputs into chunks.
+	ChunkAuto ChunkMode = iota
+
+	// ChunkStrict returns [ErrInputTooLong] for long inputs.
+	ChunkStrict
+)
+
+// Chunker parses inputs longer than the input buffer of MeCab.
+// It splits the input at safe boundaries (sentence end, newline, white space or change of the character type),
+// parses each chunk, and stitches the results together.
+// Chunker is not safe for concurrent use by multiple goroutines, as same as [MeCab].
+type Chunker struct {
+	// MeCab is the parser.
+	MeCab MeCab
+
+	// MaxBytes is the maximum length of a chunk in bytes.
+	// If it is zero, DefaultInputBufferSize is used.
+	MaxBytes int
+
+	// Mode is the behavior for long inputs.
+	Mode ChunkMode
+}
+
+// Parse parses the string and returns the result as string.
+// The result of each chunk is concatenated, without the end-of-sentence marker
+// except for the last chunk.
+func (c Chunker) Parse(s string) (string, error) {
+	chunks, err := c.split("Parse", s)
+	if err != nil {
+		return "", err
+	}
+	if len(chunks) == 1 {
+		return c.MeCab.Parse(s)
+	}
+
+	// the result of an empty string is the end-of-sentence marker.
+	eos, err := c.MeCab.Parse("")
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	for i, chunk := range chunks {
+		result, err := c.MeCab.Parse(chunk.text)
+		if err != nil {
+			return "", err
+		}
+		if i != len(chunks)-1 {
+			result = strings.TrimSuffix(result, eos)
+		}
+		buf.WriteString(result)
+	}
+	return buf.String(), nil
+}
+
+// ParseToTokens parses the string and returns the tokens, excluding BOS and EOS.
+// The offsets of the tokens are relative to the beginning of s.
+func (c Chunker) ParseToTokens(s string) ([]Token, error) {
+	chunks, err := c.split("ParseToNode", s)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []Token
+	for _, chunk := range chunks {
+		node, err := c.MeCab.ParseToNode(chunk.text)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, node.tokens(chunk.offset)...)
+	}
+	return tokens, nil
+}
+
+// ParseLattice parses the string with the lattice and returns the tokens of the best result, excluding BOS and EOS.
+// The offsets of the tokens are relative to the beginning of s.
+// The lattice contains the result of the last chunk after ParseLattice returns.
+func (c Chunker) ParseLattice(lattice Lattice, s string) ([]Token, error) {
+	chunks, err := c.split("ParseLattice", s)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []Token
+	for _, chunk := range chunks {
+		lattice.SetSentence(chunk.text)
+		if err := c.MeCab.ParseLattice(lattice); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, lattice.BOSNode().tokens(chunk.offset)...)
+	}
+	return tokens, nil
+}
+
+func (c Chunker) maxBytes() int {
+	if c.MaxBytes > 0 {
+		return c.MaxBytes
+	}
+	return DefaultInputBufferSize
+}
+
+func (c Chunker) split(op, s string) ([]chunk, error) {
+	max := c.maxBytes()
+	if len(s) <= max {
+		return []chunk{{text: s}}, nil
+	}
+	if c.Mode == ChunkStrict {
+		return nil, &Error{
+			Op:   op,
+			Kind: KindInputTooLong,
+			err:  "input is too long",
+		}
+	}
+	return splitChunks(s, max), nil
+}
+
+type chunk struct {
+	offset int
+	text   string
+}
+
+// splitChunks splits s into chunks that are shorter than or equal to max bytes.
+func splitChunks(s string, max int) []chunk {
+	var chunks []chunk
+	offset := 0
+	for len(s) > max {
+		n := chunkBoundary(s, max)
+		chunks = append(chunks, chunk{offset: offset, text: s[:n]})
+		offset += n
+		s = s[n:]
+	}
+	if len(s) > 0 {
+		chunks = append(chunks, chunk{offset: offset, text: s})
+	}
+	return chunks
+}
+
+// chunkBoundary returns the length of the first chunk of s.
+// It prefers the end of a sentence, a newline, a white space,
+// a change of the character type, and a boundary of runes in that order.
+func chunkBoundary(s string, max int) int {
+	var sentence, newline, space, typeChange, runeBoundary int
+	prevType := -1
+	for i, r := range s {
+		// an invalid byte is decoded as RuneError of width 1, not utf8.RuneLen(RuneError).
+		_, size := utf8.DecodeRuneInString(s[i:])
+		end := i + size
+		if end > max {
+			break
+		}
+
+		typ := runeType(r)
+		if prevType >= 0 && typ != prevType {
+			typeChange = i
+		}
+		prevType = typ
+
+		switch {
+		case r == '\n':
+			newline = end
+		case isSentenceEnd(r):
+			sentence = end
+		case unicode.IsSpace(r):
+			space = end
+		}
+		runeBoundary = end
+	}
+
+	for _, n := range []int{sentence, newline, space, typeChange, runeBoundary} {
+		if n > 0 {
+			return n
+		}
+	}
+
+	// max is shorter than the first rune.
+	_, size := utf8.DecodeRuneInString(s)
+	return size
+}
+
+func isSentenceEnd(r rune) bool {
+	switch r {
+	case '。', '．', '！', '？', '!', '?':
+		return true
+	}
+	return false
+}
+
+// runeType returns the rough character type of r.
+func runeType(r rune) int {
+	switch {
+	case unicode.Is(unicode.Han, r):
+		return 1
+	case unicode.Is(unicode.Hiragana, r):
+		return 2
+	case unicode.Is(unicode.Katakana, r), r == 'ー':
+		return 3
+	case unicode.IsLetter(r):
+		return 4
+	case unicode.IsDigit(r):
+		return 5
+	case unicode.IsSpace(r):
+		return 6
+	}
+	return 0
+}
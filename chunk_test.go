@@ -0,0 +1,124 @@
+package mecab
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSplitChunks(t *testing.T) {
+	tests := []struct {
+		input string
+		max   int
+		want  []string
+	}{
+		{
+			input: "こんにちは世界。さようなら世界。",
+			max:   30,
+			want:  []string{"こんにちは世界。", "さようなら世界。"},
+		},
+		{
+			input: "hello world\nfoo bar",
+			max:   14,
+			want:  []string{"hello world\n", "foo bar"},
+		},
+		{
+			input: "hello world foo bar",
+			max:   14,
+			want:  []string{"hello world ", "foo bar"},
+		},
+		{
+			input: "こんにちは世界",
+			max:   18,
+			want:  []string{"こんにちは", "世界"},
+		},
+		{
+			input: "ああああ",
+			max:   7,
+			want:  []string{"ああ", "ああ"},
+		},
+		{
+			input: "あ",
+			max:   1,
+			want:  []string{"あ"},
+		},
+		{
+			// the invalid bytes are one byte long.
+			input: "--\xff--",
+			max:   3,
+			want:  []string{"--\xff", "--"},
+		},
+		{
+			input: "ab\n\xff\ncd",
+			max:   5,
+			want:  []string{"ab\n\xff\n", "cd"},
+		},
+	}
+
+	for _, tt := range tests {
+		chunks := splitChunks(tt.input, tt.max)
+		var got []string
+		offset := 0
+		for _, c := range chunks {
+			if c.offset != offset {
+				t.Errorf("%q: want offset %d, got %d", tt.input, offset, c.offset)
+			}
+			offset += len(c.text)
+			got = append(got, c.text)
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%q: want %q, got %q", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestChunker_strict(t *testing.T) {
+	c := Chunker{
+		MaxBytes: 10,
+		Mode:     ChunkStrict,
+	}
+	_, err := c.ParseToTokens(strings.Repeat("あ", 10))
+	if !errors.Is(err, ErrInputTooLong) {
+		t.Errorf("want ErrInputTooLong, got %v", err)
+	}
+}
+
+func TestChunker(t *testing.T) {
+	mecab, err := New(rcfile(map[string]string{}))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer mecab.Destroy()
+
+	// XXX: avoid GC, MeCab 0.996 has GC problem (see https://github.com/taku910/mecab/pull/24)
+	mecab.Parse("")
+
+	input := strings.Repeat("こんにちは世界。", 100)
+	c := Chunker{
+		MeCab:    mecab,
+		MaxBytes: 100,
+	}
+	tokens, err := c.ParseToTokens(input)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	if len(tokens) != 300 {
+		t.Errorf("want 300 tokens, got %d", len(tokens))
+	}
+	for _, token := range tokens {
+		if input[token.Start:token.End] != token.Surface {
+			t.Errorf("want %q at %d, got %q", token.Surface, token.Start, input[token.Start:token.End])
+		}
+	}
+
+	result, err := c.Parse(input)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	if n := strings.Count(result, "EOS\n"); n != 1 {
+		t.Errorf("want 1 EOS, got %d", n)
+	}
+}
@@ -0,0 +1,110 @@
+package dic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// categoryNameSize is the size of a category name in char.bin.
+const categoryNameSize = 32
+
+// charMapSize is the number of the characters in char.bin, which covers the BMP.
+const charMapSize = 0xffff
+
+// CharInfo is the character information.
+type CharInfo struct {
+	// Type is the bitmask of the categories.
+	Type uint32
+
+	// DefaultType is the index of the default category.
+	DefaultType int
+
+	// Length is the maximum length of the unknown words.
+	Length int
+
+	// Group means that the characters of the same category are grouped into an unknown word.
+	Group bool
+
+	// Invoke means that the unknown word processing is invoked even if a known word is found.
+	Invoke bool
+}
+
+// IsKindOf reports whether the character belongs to the i-th category.
+func (c CharInfo) IsKindOf(i int) bool {
+	return c.Type&(1<<uint(i)) != 0
+}
+
+// CharProperty is the character definitions (char.bin).
+type CharProperty struct {
+	data       *mappedFile
+	categories []string
+	m          []byte
+}
+
+// OpenCharProperty opens the character definition file.
+func OpenCharProperty(name string) (*CharProperty, error) {
+	f, err := mapFile(name)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newCharProperty(f.data)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("dic: %s: %w", name, err)
+	}
+	c.data = f
+	return c, nil
+}
+
+func newCharProperty(data []byte) (*CharProperty, error) {
+	if len(data) < 4 {
+		return nil, errBroken
+	}
+	csize := int(binary.LittleEndian.Uint32(data))
+	if len(data) != 4+categoryNameSize*csize+4*charMapSize {
+		return nil, fmt.Errorf("%w: invalid size", errBroken)
+	}
+	categories := make([]string, csize)
+	for i := range categories {
+		b := data[4+categoryNameSize*i : 4+categoryNameSize*(i+1)]
+		name, _, _ := bytes.Cut(b, []byte{0})
+		categories[i] = string(name)
+	}
+	return &CharProperty{
+		categories: categories,
+		m:          data[4+categoryNameSize*csize:],
+	}, nil
+}
+
+// Close closes the character definitions. It must not be used after it is closed.
+func (c *CharProperty) Close() error {
+	if c.data == nil {
+		return nil
+	}
+	err := c.data.Close()
+	c.data = nil
+	c.m = nil
+	return err
+}
+
+// Categories returns the names of the categories.
+func (c *CharProperty) Categories() []string {
+	return c.categories
+}
+
+// Info returns the information of the character.
+// The characters out of the BMP have the information of U+0000.
+func (c *CharProperty) Info(r rune) CharInfo {
+	if r < 0 || r >= charMapSize {
+		r = 0
+	}
+	v := binary.LittleEndian.Uint32(c.m[4*r:])
+	return CharInfo{
+		Type:        v & (1<<18 - 1),
+		DefaultType: int(v >> 18 & 0xff),
+		Length:      int(v >> 26 & 0xf),
+		Group:       v>>30&1 != 0,
+		Invoke:      v>>31&1 != 0,
+	}
+}
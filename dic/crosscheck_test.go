@@ -0,0 +1,91 @@
+//go:build cgo && !purego
+
+package dic_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shogo82148/go-mecab"
+	"github.com/shogo82148/go-mecab/dic"
+)
+
+// TestCrossCheck compares the headers with DictionaryInfo of libmecab.
+func TestCrossCheck(t *testing.T) {
+	loc, err := mecab.DiscoverDictionary()
+	if err != nil {
+		t.Skip("no dictionary is found")
+	}
+	model, err := mecab.NewModelFromDicdir(loc.Dir, map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer model.Destroy()
+
+	d, err := dic.OpenDir(loc.Dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer d.Close()
+
+	var info mecab.DictionaryInfo
+	for _, i := range model.DictionaryInfo() {
+		if i.Type == mecab.SystemDictionary {
+			info = i
+		}
+	}
+	if filepath.Base(info.Filename) != "sys.dic" {
+		t.Fatalf("system dictionary is not found: %#v", info)
+	}
+
+	h := d.System.Header
+	if int(h.LexSize) != info.Size {
+		t.Errorf("LexSize: want %d, got %d", info.Size, h.LexSize)
+	}
+	if int(h.LSize) != info.LSize || int(h.RSize) != info.RSize {
+		t.Errorf("LSize, RSize: want %d, %d, got %d, %d", info.LSize, info.RSize, h.LSize, h.RSize)
+	}
+	if int(h.Version) != info.Version {
+		t.Errorf("Version: want %d, got %d", info.Version, h.Version)
+	}
+	if h.Charset != info.Charset {
+		t.Errorf("Charset: want %q, got %q", info.Charset, h.Charset)
+	}
+	if d.Matrix.LSize() != info.LSize || d.Matrix.RSize() != info.RSize {
+		t.Errorf("matrix size: want %d, %d, got %d, %d", info.LSize, info.RSize, d.Matrix.LSize(), d.Matrix.RSize())
+	}
+	if d.Unknown.Type != dic.Unknown {
+		t.Errorf("want unknown dictionary, got %s", d.Unknown.Type)
+	}
+	if len(d.Char.Categories()) == 0 || d.Char.Categories()[0] != "DEFAULT" {
+		t.Errorf("unexpected categories: %v", d.Char.Categories())
+	}
+
+	// the tokens of a surface must be found in both.
+	if !strings.EqualFold(strings.ReplaceAll(h.Charset, "-", ""), "utf8") {
+		t.Skip("the dictionary is not encoded in UTF-8")
+	}
+	mecabTagger, err := model.NewMeCab()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer mecabTagger.Destroy()
+	node, err := mecabTagger.ParseToNode("東京")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	node = node.Next()
+	if node.Stat() != mecab.NormalNode {
+		t.Skip("東京 is not in the dictionary")
+	}
+	found := false
+	for _, token := range d.System.ExactMatch(node.Surface()) {
+		if d.System.Feature(token) == node.Feature() && int(token.WCost) == node.WCost() {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("token of %q is not found", node.Surface())
+	}
+}
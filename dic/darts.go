@@ -0,0 +1,82 @@
+package dic
+
+import (
+	"encoding/binary"
+	"iter"
+)
+
+// unitSize is the size of a unit of the double-array: int32 base and uint32 check.
+const unitSize = 8
+
+// DoubleArray is the double-array trie of Darts, which MeCab uses for the dictionary.
+type DoubleArray []byte
+
+// Len returns the number of the units.
+func (da DoubleArray) Len() int {
+	return len(da) / unitSize
+}
+
+// Unit returns the base and the check of the i-th unit.
+func (da DoubleArray) Unit(i int) (base int32, check uint32) {
+	b := da[i*unitSize : (i+1)*unitSize]
+	return int32(binary.LittleEndian.Uint32(b[0:])), binary.LittleEndian.Uint32(b[4:])
+}
+
+// unit returns the unit at p, or ok = false if p is out of range.
+func (da DoubleArray) unit(p int64) (base int32, check uint32, ok bool) {
+	if p < 0 || p >= int64(da.Len()) {
+		return 0, 0, false
+	}
+	base, check = da.Unit(int(p))
+	return base, check, true
+}
+
+// ExactMatch returns the value of the key.
+func (da DoubleArray) ExactMatch(key string) (int32, bool) {
+	if da.Len() == 0 {
+		return 0, false
+	}
+	b, _ := da.Unit(0)
+	for i := 0; i < len(key); i++ {
+		p := int64(b) + int64(key[i]) + 1
+		base, check, ok := da.unit(p)
+		if !ok || int64(check) != int64(b) {
+			return 0, false
+		}
+		b = base
+	}
+	n, check, ok := da.unit(int64(b))
+	if !ok || int64(check) != int64(b) || n >= 0 {
+		return 0, false
+	}
+	return -n - 1, true
+}
+
+// CommonPrefixSearch returns an iterator over the values and the lengths of the prefixes of the key,
+// from the shortest prefix.
+func (da DoubleArray) CommonPrefixSearch(key string) iter.Seq2[int32, int] {
+	return func(yield func(int32, int) bool) {
+		if da.Len() == 0 {
+			return
+		}
+		b, _ := da.Unit(0)
+		for i := 0; i < len(key); i++ {
+			n, check, ok := da.unit(int64(b))
+			if ok && int64(check) == int64(b) && n < 0 {
+				if !yield(-n-1, i) {
+					return
+				}
+			}
+			p := int64(b) + int64(key[i]) + 1
+			base, check, ok := da.unit(p)
+			if !ok || int64(check) != int64(b) {
+				return
+			}
+			b = base
+		}
+		n, check, ok := da.unit(int64(b))
+		if ok && int64(check) == int64(b) && n < 0 {
+			yield(-n-1, len(key))
+		}
+	}
+}
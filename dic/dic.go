@@ -0,0 +1,309 @@
+// Package dic reads the compiled dictionaries of MeCab without cgo.
+//
+// It supports sys.dic, unk.dic and user dictionaries compiled by mecab-dict-index,
+// the connection matrix (matrix.bin) and the character definitions (char.bin).
+// The files are memory-mapped if the platform supports it.
+// The data is assumed to be little endian, as mecab-dict-index writes it on most platforms.
+package dic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// dictionaryMagicID is xor-ed with the file size in the header.
+const dictionaryMagicID = 0xef718f77
+
+// Version is the version of the dictionary format that this package supports.
+const Version = 102
+
+// headerSize is the size of the dictionary header: ten uint32 values and charset[32].
+const headerSize = 10*4 + 32
+
+// tokenSize is the size of a token: lcAttr, rcAttr, posid, wcost, feature and compound.
+const tokenSize = 16
+
+// Type is a type of dictionary.
+type Type uint32
+
+const (
+	// System is the system dictionary (sys.dic).
+	System Type = 0
+
+	// User is a user dictionary.
+	User Type = 1
+
+	// Unknown is the dictionary for unknown words (unk.dic).
+	Unknown Type = 2
+)
+
+func (t Type) String() string {
+	switch t {
+	case System:
+		return "System"
+	case User:
+		return "User"
+	case Unknown:
+		return "Unknown"
+	}
+	return ""
+}
+
+// Header is the header of a dictionary.
+type Header struct {
+	// Magic is the file size xor-ed with the magic number.
+	Magic uint32
+
+	// Version is the version of the format.
+	Version uint32
+
+	// Type is the type of the dictionary.
+	Type Type
+
+	// LexSize is the number of the tokens.
+	LexSize uint32
+
+	// LSize and RSize are the number of the left and right context IDs.
+	LSize uint32
+	RSize uint32
+
+	// DSize, TSize and FSize are the sizes in bytes of the double-array trie,
+	// the token table and the feature strings.
+	DSize uint32
+	TSize uint32
+	FSize uint32
+
+	// Charset is the charset of the dictionary.
+	Charset string
+}
+
+// Token is an entry of the token table.
+type Token struct {
+	// LCAttr and RCAttr are the left and right context IDs.
+	LCAttr uint16
+	RCAttr uint16
+
+	// PosID is the part-of-speech ID.
+	PosID uint16
+
+	// WCost is the word cost.
+	WCost int16
+
+	// Feature is the offset of the feature string. Use [Dictionary.Feature] to get it.
+	Feature uint32
+
+	// Compound is reserved for compound words.
+	Compound uint32
+}
+
+// Match is a result of the common prefix search.
+type Match struct {
+	// Length is the length in bytes of the matched prefix.
+	Length int
+
+	// Tokens are the tokens of the prefix.
+	Tokens []Token
+}
+
+// Dictionary is a compiled dictionary, e.g. sys.dic, unk.dic or a user dictionary.
+type Dictionary struct {
+	Header
+
+	data     *mappedFile
+	trie     DoubleArray
+	tokens   []byte
+	features []byte
+}
+
+// Open opens the dictionary file.
+func Open(name string) (*Dictionary, error) {
+	f, err := mapFile(name)
+	if err != nil {
+		return nil, err
+	}
+	d, err := newDictionary(f.data)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("dic: %s: %w", name, err)
+	}
+	d.data = f
+	return d, nil
+}
+
+var errBroken = errors.New("broken dictionary")
+
+func newDictionary(data []byte) (*Dictionary, error) {
+	if len(data) < headerSize {
+		return nil, errBroken
+	}
+	le := binary.LittleEndian
+	charset, _, _ := bytes.Cut(data[40:headerSize], []byte{0})
+	h := Header{
+		Magic:   le.Uint32(data[0:]),
+		Version: le.Uint32(data[4:]),
+		Type:    Type(le.Uint32(data[8:])),
+		LexSize: le.Uint32(data[12:]),
+		LSize:   le.Uint32(data[16:]),
+		RSize:   le.Uint32(data[20:]),
+		DSize:   le.Uint32(data[24:]),
+		TSize:   le.Uint32(data[28:]),
+		FSize:   le.Uint32(data[32:]),
+		Charset: string(charset),
+	}
+	if h.Magic^dictionaryMagicID != uint32(len(data)) {
+		return nil, fmt.Errorf("%w: invalid magic number", errBroken)
+	}
+	if h.Version != Version {
+		return nil, fmt.Errorf("incompatible version: %d", h.Version)
+	}
+	size := uint64(headerSize) + uint64(h.DSize) + uint64(h.TSize) + uint64(h.FSize)
+	if size > uint64(len(data)) || uint64(h.TSize) != uint64(h.LexSize)*tokenSize {
+		return nil, fmt.Errorf("%w: invalid size", errBroken)
+	}
+
+	offset := uint32(headerSize)
+	da := data[offset : offset+h.DSize]
+	offset += h.DSize
+	tokens := data[offset : offset+h.TSize]
+	offset += h.TSize
+	features := data[offset : offset+h.FSize]
+
+	return &Dictionary{
+		Header:   h,
+		trie:     DoubleArray(da),
+		tokens:   tokens,
+		features: features,
+	}, nil
+}
+
+// Close closes the dictionary. The dictionary must not be used after it is closed.
+func (d *Dictionary) Close() error {
+	if d.data == nil {
+		return nil
+	}
+	err := d.data.Close()
+	d.data = nil
+	d.trie, d.tokens, d.features = nil, nil, nil
+	return err
+}
+
+// Trie returns the double-array trie of the dictionary.
+func (d *Dictionary) Trie() DoubleArray {
+	return d.trie
+}
+
+// NumTokens returns the number of the tokens.
+func (d *Dictionary) NumTokens() int {
+	return len(d.tokens) / tokenSize
+}
+
+// Token returns the i-th token.
+func (d *Dictionary) Token(i int) Token {
+	b := d.tokens[i*tokenSize : (i+1)*tokenSize]
+	le := binary.LittleEndian
+	return Token{
+		LCAttr:   le.Uint16(b[0:]),
+		RCAttr:   le.Uint16(b[2:]),
+		PosID:    le.Uint16(b[4:]),
+		WCost:    int16(le.Uint16(b[6:])),
+		Feature:  le.Uint32(b[8:]),
+		Compound: le.Uint32(b[12:]),
+	}
+}
+
+// Feature returns the feature string of the token, in the charset of the dictionary.
+func (d *Dictionary) Feature(t Token) string {
+	if int(t.Feature) >= len(d.features) {
+		return ""
+	}
+	b := d.features[t.Feature:]
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// tokensOf returns the tokens of the value of the trie.
+// The upper 24 bits of the value are the index of the first token,
+// and the lower 8 bits are the number of the tokens.
+func (d *Dictionary) tokensOf(value int32) []Token {
+	start := int(value >> 8)
+	n := int(value & 0xff)
+	tokens := make([]Token, 0, n)
+	for i := start; i < start+n && i < d.NumTokens(); i++ {
+		tokens = append(tokens, d.Token(i))
+	}
+	return tokens
+}
+
+// ExactMatch returns the tokens of the key.
+func (d *Dictionary) ExactMatch(key string) []Token {
+	value, ok := d.trie.ExactMatch(key)
+	if !ok {
+		return nil
+	}
+	return d.tokensOf(value)
+}
+
+// CommonPrefixSearch returns the tokens of the prefixes of the key, from the shortest prefix.
+func (d *Dictionary) CommonPrefixSearch(key string) []Match {
+	var matches []Match
+	for value, length := range d.trie.CommonPrefixSearch(key) {
+		matches = append(matches, Match{
+			Length: length,
+			Tokens: d.tokensOf(value),
+		})
+	}
+	return matches
+}
+
+// Dir is a set of the files of a compiled dictionary directory.
+type Dir struct {
+	System  *Dictionary
+	Unknown *Dictionary
+	Matrix  *Matrix
+	Char    *CharProperty
+}
+
+// OpenDir opens sys.dic, unk.dic, matrix.bin and char.bin in the dictionary directory.
+func OpenDir(dicdir string) (*Dir, error) {
+	d := &Dir{}
+	var err error
+	if d.System, err = Open(filepath.Join(dicdir, "sys.dic")); err != nil {
+		return nil, err
+	}
+	if d.Unknown, err = Open(filepath.Join(dicdir, "unk.dic")); err != nil {
+		d.Close()
+		return nil, err
+	}
+	if d.Matrix, err = OpenMatrix(filepath.Join(dicdir, "matrix.bin")); err != nil {
+		d.Close()
+		return nil, err
+	}
+	if d.Char, err = OpenCharProperty(filepath.Join(dicdir, "char.bin")); err != nil {
+		d.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// Close closes the files.
+func (d *Dir) Close() error {
+	var errs []error
+	if d.System != nil {
+		errs = append(errs, d.System.Close())
+	}
+	if d.Unknown != nil {
+		errs = append(errs, d.Unknown.Close())
+	}
+	if d.Matrix != nil {
+		errs = append(errs, d.Matrix.Close())
+	}
+	if d.Char != nil {
+		errs = append(errs, d.Char.Close())
+	}
+	return errors.Join(errs...)
+}
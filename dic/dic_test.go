@@ -0,0 +1,150 @@
+package dic
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/shogo82148/go-mecab/internal/dictest"
+)
+
+type testEntry struct {
+	surface string
+	token   Token
+	feature string
+}
+
+// buildDictionary builds a dictionary file for the test.
+func buildDictionary(typ Type, entries []testEntry) []byte {
+	var e []dictest.Entry
+	for _, entry := range entries {
+		e = append(e, dictest.Entry{
+			Surface: entry.surface,
+			LCAttr:  entry.token.LCAttr,
+			RCAttr:  entry.token.RCAttr,
+			PosID:   entry.token.PosID,
+			WCost:   entry.token.WCost,
+			Feature: entry.feature,
+		})
+	}
+	return dictest.Dictionary(uint32(typ), 3, 4, "UTF-8", e)
+}
+
+var testEntries = []testEntry{
+	{"東京", Token{LCAttr: 1, RCAttr: 1, PosID: 38, WCost: 3000}, "名詞,固有名詞,地域,一般,*,*,東京,トウキョウ,トーキョー"},
+	{"東京都", Token{LCAttr: 1, RCAttr: 1, PosID: 38, WCost: 4000}, "名詞,固有名詞,地域,一般,*,*,東京都,トウキョウト,トーキョート"},
+	{"都", Token{LCAttr: 2, RCAttr: 2, PosID: 51, WCost: 5000}, "名詞,接尾,地域,*,*,*,都,ト,ト"},
+	{"都", Token{LCAttr: 3, RCAttr: 3, PosID: 38, WCost: -100}, "名詞,一般,*,*,*,*,都,ミヤコ,ミヤコ"},
+}
+
+func TestDictionary(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "sys.dic")
+	if err := os.WriteFile(name, buildDictionary(System, testEntries), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	if d.Type != System || d.LexSize != 4 || d.LSize != 3 || d.RSize != 4 || d.Charset != "UTF-8" {
+		t.Errorf("unexpected header: %#v", d.Header)
+	}
+	if d.NumTokens() != 4 {
+		t.Errorf("want 4 tokens, got %d", d.NumTokens())
+	}
+	for i, e := range testEntries {
+		token := d.Token(i)
+		if token.WCost != e.token.WCost || token.PosID != e.token.PosID {
+			t.Errorf("%d: unexpected token: %#v", i, token)
+		}
+		if got := d.Feature(token); got != e.feature {
+			t.Errorf("%d: want %q, got %q", i, e.feature, got)
+		}
+	}
+
+	tokens := d.ExactMatch("都")
+	if len(tokens) != 2 || tokens[0].WCost != 5000 || tokens[1].WCost != -100 {
+		t.Errorf("unexpected tokens: %#v", tokens)
+	}
+	if tokens := d.ExactMatch("東"); tokens != nil {
+		t.Errorf("want nil, got %#v", tokens)
+	}
+
+	matches := d.CommonPrefixSearch("東京都庁")
+	var lengths []int
+	for _, m := range matches {
+		lengths = append(lengths, m.Length)
+	}
+	if want := []int{len("東京"), len("東京都")}; !slices.Equal(lengths, want) {
+		t.Errorf("want %v, got %v", want, lengths)
+	}
+}
+
+func TestDictionary_broken(t *testing.T) {
+	data := buildDictionary(System, testEntries)
+	if _, err := newDictionary(data[:len(data)-1]); err == nil {
+		t.Error("want error for the truncated dictionary")
+	}
+	if _, err := newDictionary(data[:10]); err == nil {
+		t.Error("want error for the short dictionary")
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	data := binary.LittleEndian.AppendUint16(nil, 2)
+	data = binary.LittleEndian.AppendUint16(data, 3)
+	for i := range 6 {
+		data = binary.LittleEndian.AppendUint16(data, uint16(int16(i*100-200)))
+	}
+	m, err := newMatrix(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.LSize() != 2 || m.RSize() != 3 {
+		t.Errorf("unexpected size: %d, %d", m.LSize(), m.RSize())
+	}
+	if got := m.Cost(1, 2); got != 300 {
+		t.Errorf("want 300, got %d", got)
+	}
+	if got := m.Cost(0, 0); got != -200 {
+		t.Errorf("want -200, got %d", got)
+	}
+}
+
+func TestCharProperty(t *testing.T) {
+	data := binary.LittleEndian.AppendUint32(nil, 2)
+	for _, name := range []string{"DEFAULT", "KANJI"} {
+		var b [categoryNameSize]byte
+		copy(b[:], name)
+		data = append(data, b[:]...)
+	}
+	for r := range charMapSize {
+		var v uint32 = 1 // DEFAULT
+		if r >= 0x4e00 && r <= 0x9fff {
+			v = 1<<1 | 1<<18 | 2<<26 | 1<<31 // KANJI, invoke
+		}
+		data = binary.LittleEndian.AppendUint32(data, v)
+	}
+	c, err := newCharProperty(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"DEFAULT", "KANJI"}; !slices.Equal(c.Categories(), want) {
+		t.Errorf("want %v, got %v", want, c.Categories())
+	}
+	info := c.Info('漢')
+	want := CharInfo{Type: 2, DefaultType: 1, Length: 2, Invoke: true}
+	if info != want || !info.IsKindOf(1) {
+		t.Errorf("want %#v, got %#v", want, info)
+	}
+	if info := c.Info('a'); info.Type != 1 || info.DefaultType != 0 {
+		t.Errorf("unexpected info: %#v", info)
+	}
+	if info := c.Info('😀'); info != c.Info(0) {
+		t.Errorf("unexpected info: %#v", info)
+	}
+}
@@ -0,0 +1,73 @@
+package dic
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Matrix is the connection matrix (matrix.bin).
+type Matrix struct {
+	data   *mappedFile
+	lsize  int
+	rsize  int
+	matrix []byte
+}
+
+// OpenMatrix opens the connection matrix file.
+func OpenMatrix(name string) (*Matrix, error) {
+	f, err := mapFile(name)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMatrix(f.data)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("dic: %s: %w", name, err)
+	}
+	m.data = f
+	return m, nil
+}
+
+func newMatrix(data []byte) (*Matrix, error) {
+	if len(data) < 4 {
+		return nil, errBroken
+	}
+	lsize := int(binary.LittleEndian.Uint16(data[0:]))
+	rsize := int(binary.LittleEndian.Uint16(data[2:]))
+	if len(data) != 4+2*lsize*rsize {
+		return nil, fmt.Errorf("%w: invalid size", errBroken)
+	}
+	return &Matrix{
+		lsize:  lsize,
+		rsize:  rsize,
+		matrix: data[4:],
+	}, nil
+}
+
+// Close closes the matrix. The matrix must not be used after it is closed.
+func (m *Matrix) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	err := m.data.Close()
+	m.data = nil
+	m.matrix = nil
+	return err
+}
+
+// LSize returns the number of the right context IDs of the left nodes.
+func (m *Matrix) LSize() int {
+	return m.lsize
+}
+
+// RSize returns the number of the left context IDs of the right nodes.
+func (m *Matrix) RSize() int {
+	return m.rsize
+}
+
+// Cost returns the connection cost between the left node with the right context ID rcAttr
+// and the right node with the left context ID lcAttr.
+func (m *Matrix) Cost(rcAttr, lcAttr int) int {
+	i := rcAttr + m.lsize*lcAttr
+	return int(int16(binary.LittleEndian.Uint16(m.matrix[2*i:])))
+}
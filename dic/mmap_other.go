@@ -0,0 +1,23 @@
+//go:build !unix
+
+package dic
+
+import "os"
+
+type mappedFile struct {
+	data []byte
+}
+
+// mapFile reads the whole file, because memory-mapping is not supported.
+func mapFile(name string) (*mappedFile, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return &mappedFile{data: data}, nil
+}
+
+func (f *mappedFile) Close() error {
+	f.data = nil
+	return nil
+}
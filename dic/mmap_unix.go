@@ -0,0 +1,43 @@
+//go:build unix
+
+package dic
+
+import (
+	"os"
+	"syscall"
+)
+
+type mappedFile struct {
+	data []byte
+}
+
+func mapFile(name string) (*mappedFile, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := stat.Size()
+	if size == 0 {
+		return &mappedFile{}, nil
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: name, Err: err}
+	}
+	return &mappedFile{data: data}, nil
+}
+
+func (f *mappedFile) Close() error {
+	if f.data == nil {
+		return nil
+	}
+	err := syscall.Munmap(f.data)
+	f.data = nil
+	return err
+}
@@ -0,0 +1,51 @@
+package mecab
+
+// DictionaryType is a type of dictionary.
+type DictionaryType int
+
+const (
+	// SystemDictionary is the system dictionary (sys.dic).
+	SystemDictionary DictionaryType = 0
+
+	// UserDictionary is a user dictionary.
+	UserDictionary DictionaryType = 1
+
+	// UnknownDictionary is the dictionary for unknown words (unk.dic).
+	UnknownDictionary DictionaryType = 2
+)
+
+func (t DictionaryType) String() string {
+	switch t {
+	case SystemDictionary:
+		return "System"
+	case UserDictionary:
+		return "User"
+	case UnknownDictionary:
+		return "Unknown"
+	}
+	return ""
+}
+
+// DictionaryInfo is information of a dictionary.
+type DictionaryInfo struct {
+	// Filename is the filename of the dictionary.
+	Filename string
+
+	// Charset is the character set of the dictionary, e.g. "UTF-8" or "EUC-JP".
+	Charset string
+
+	// Size is the number of the words in the dictionary.
+	Size int
+
+	// Type is the type of the dictionary.
+	Type DictionaryType
+
+	// LSize is the size of the left attributes.
+	LSize int
+
+	// RSize is the size of the right attributes.
+	RSize int
+
+	// Version is the version of the dictionary.
+	Version int
+}
@@ -0,0 +1,46 @@
+//go:build cgo && !purego
+
+package mecab
+
+// #include <mecab.h>
+import "C"
+
+import "runtime"
+
+func newDictionaryInfo(info *C.mecab_dictionary_info_t) []DictionaryInfo {
+	var ret []DictionaryInfo
+	for ; info != nil; info = info.next {
+		ret = append(ret, DictionaryInfo{
+			Filename: C.GoString(info.filename),
+			Charset:  C.GoString(info.charset),
+			Size:     int(info.size),
+			Type:     DictionaryType(info._type),
+			LSize:    int(info.lsize),
+			RSize:    int(info.rsize),
+			Version:  int(info.version),
+		})
+	}
+	return ret
+}
+
+// DictionaryInfo returns the information of the dictionaries.
+// The first one is the system dictionary.
+func (m MeCab) DictionaryInfo() []DictionaryInfo {
+	if m.m.mecab == nil {
+		panic(errMeCabNotAvailable)
+	}
+	info := newDictionaryInfo(C.mecab_dictionary_info(m.m.mecab))
+	runtime.KeepAlive(m.m)
+	return info
+}
+
+// DictionaryInfo returns the information of the dictionaries.
+// The first one is the system dictionary.
+func (m Model) DictionaryInfo() []DictionaryInfo {
+	if m.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+	info := newDictionaryInfo(C.mecab_model_dictionary_info(m.m.model))
+	runtime.KeepAlive(m.m)
+	return info
+}
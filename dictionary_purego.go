@@ -0,0 +1,41 @@
+//go:build !cgo || purego
+
+package mecab
+
+import "github.com/shogo82148/go-mecab/internal/viterbi"
+
+func newDictionaryInfo(info []viterbi.DictionaryInfo) []DictionaryInfo {
+	var ret []DictionaryInfo
+	for _, info := range info {
+		ret = append(ret, DictionaryInfo{
+			Filename: info.Filename,
+			Charset:  info.Charset,
+			Size:     int(info.Size),
+			Type:     DictionaryType(info.Type),
+			LSize:    int(info.LSize),
+			RSize:    int(info.RSize),
+			Version:  int(info.Version),
+		})
+	}
+	return ret
+}
+
+// DictionaryInfo returns the information of the dictionaries.
+// The first one is the system dictionary.
+func (m MeCab) DictionaryInfo() []DictionaryInfo {
+	if m.m.mecab == nil {
+		panic(errMeCabNotAvailable)
+	}
+	return Model{m: m.m.mecab.model}.DictionaryInfo()
+}
+
+// DictionaryInfo returns the information of the dictionaries.
+// The first one is the system dictionary.
+func (m Model) DictionaryInfo() []DictionaryInfo {
+	m.m.mu.RLock()
+	defer m.m.mu.RUnlock()
+	if m.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+	return newDictionaryInfo(m.m.model.DictionaryInfo())
+}
@@ -0,0 +1,310 @@
+package mecab
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ChangeKind is a kind of the difference of the tokens between two models.
+type ChangeKind int
+
+const (
+	// ChangeSplit means that a token of the old model is split into some tokens.
+	ChangeSplit ChangeKind = iota + 1
+
+	// ChangeMerge means that some tokens of the old model are merged into a token.
+	ChangeMerge
+
+	// ChangeResegment means that the boundaries of the tokens are moved,
+	// e.g. "に 行く" to "に行 く".
+	ChangeResegment
+
+	// ChangePOS means that the segmentation is same, but the features are changed.
+	ChangePOS
+)
+
+func (kind ChangeKind) String() string {
+	switch kind {
+	case ChangeSplit:
+		return "split"
+	case ChangeMerge:
+		return "merge"
+	case ChangeResegment:
+		return "resegment"
+	case ChangePOS:
+		return "pos"
+	}
+	return ""
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (kind ChangeKind) MarshalText() ([]byte, error) {
+	s := kind.String()
+	if s == "" {
+		return nil, fmt.Errorf("mecab: invalid change kind: %d", int(kind))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (kind *ChangeKind) UnmarshalText(text []byte) error {
+	for k := ChangeSplit; k <= ChangePOS; k++ {
+		if k.String() == string(text) {
+			*kind = k
+			return nil
+		}
+	}
+	return fmt.Errorf("mecab: invalid change kind: %q", text)
+}
+
+// ModelDiffer compares the best paths of two models over a corpus,
+// e.g. to review an upgrade of the system dictionary or a change of a user dictionary.
+type ModelDiffer struct {
+	// FeatureFields is the number of the feature fields compared for [ChangePOS].
+	// If it is zero, the whole features are compared.
+	FeatureFields int
+
+	// TopChanges is the number of the changes in the summary.
+	// If it is zero, 20 is used. If it is negative, all the changes are reported.
+	TopChanges int
+}
+
+// ModelDiff is the result of [ModelDiffer.Diff].
+type ModelDiff struct {
+	// Sentences is the number of the sentences in the corpus.
+	Sentences int `json:"sentences"`
+
+	// Diffs are the sentences whose best paths differ, in the order of the corpus.
+	Diffs []SentenceDiff `json:"diffs"`
+
+	// Summary is the most frequent changes over the corpus.
+	Summary []ChangeCount `json:"summary"`
+}
+
+// SentenceDiff is a sentence whose best paths differ between the models.
+type SentenceDiff struct {
+	// Line is the line number of the sentence in the corpus, starting at 1.
+	Line int `json:"line"`
+
+	// Sentence is the sentence.
+	Sentence string `json:"sentence"`
+
+	// Old and New are the tokens of the old and new models.
+	Old []Token `json:"old"`
+	New []Token `json:"new"`
+
+	// Changes are the changes of the tokens, aligned at the token level.
+	Changes []TokenChange `json:"changes"`
+}
+
+// TokenChange is a group of the tokens that have the same boundaries
+// at the both ends, but differ between the models.
+type TokenChange struct {
+	Kind ChangeKind `json:"kind"`
+
+	// Old and New are the tokens of the old and new models.
+	Old []Token `json:"old"`
+	New []Token `json:"new"`
+}
+
+// ChangeCount is the frequency of a change.
+// Old and New are the surfaces separated by spaces for the segmentation changes,
+// and the features for [ChangePOS].
+type ChangeCount struct {
+	Kind  ChangeKind `json:"kind"`
+	Old   string     `json:"old"`
+	New   string     `json:"new"`
+	Count int        `json:"count"`
+}
+
+// String returns the summary of the differences.
+func (d *ModelDiff) String() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%d/%d sentences changed\n", len(d.Diffs), d.Sentences)
+	if len(d.Summary) > 0 {
+		buf.WriteString("\nchanges (old -> new):\n")
+		for _, c := range d.Summary {
+			fmt.Fprintf(&buf, "%6d  %-9s  %s -> %s\n", c.Count, c.Kind, c.Old, c.New)
+		}
+	}
+	return buf.String()
+}
+
+// WriteJSON writes the differences in JSON for review.
+func (d *ModelDiff) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(d)
+}
+
+// DiffModels compares the models over the corpus with the default settings.
+// It is a shortcut of [ModelDiffer.Diff].
+func DiffModels(before, after Model, corpus io.Reader) (*ModelDiff, error) {
+	var d ModelDiffer
+	return d.Diff(before, after, corpus)
+}
+
+// Diff parses each line of the corpus with the old model (before) and the new model (after),
+// and reports the sentences whose best paths differ.
+// The empty lines are skipped.
+func (d *ModelDiffer) Diff(before, after Model, corpus io.Reader) (*ModelDiff, error) {
+	const op = "DiffModels"
+
+	oldTagger, err := before.NewMeCab()
+	if err != nil {
+		return nil, err
+	}
+	defer oldTagger.Destroy()
+	newTagger, err := after.NewMeCab()
+	if err != nil {
+		return nil, err
+	}
+	defer newTagger.Destroy()
+
+	ret := &ModelDiff{}
+	counts := map[ChangeCount]int{}
+	s := bufio.NewScanner(corpus)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	n := 0
+	for s.Scan() {
+		n++
+		line := strings.TrimSuffix(s.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		ret.Sentences++
+
+		node, err := oldTagger.ParseToNode(line)
+		if err != nil {
+			return nil, lineError(op, n, "old model", err)
+		}
+		oldTokens := node.Tokens()
+		node, err = newTagger.ParseToNode(line)
+		if err != nil {
+			return nil, lineError(op, n, "new model", err)
+		}
+		newTokens := node.Tokens()
+
+		changes := d.diffTokens(oldTokens, newTokens)
+		if len(changes) == 0 {
+			continue
+		}
+		for _, c := range changes {
+			counts[d.changeCount(c)]++
+		}
+		ret.Diffs = append(ret.Diffs, SentenceDiff{
+			Line:     n,
+			Sentence: line,
+			Old:      oldTokens,
+			New:      newTokens,
+			Changes:  changes,
+		})
+	}
+	if err := s.Err(); err != nil {
+		return nil, &Error{
+			Op:  op,
+			err: err.Error(),
+		}
+	}
+
+	top := d.TopChanges
+	if top == 0 {
+		top = 20
+	}
+	ret.Summary = topChangeCounts(counts, top)
+	return ret, nil
+}
+
+// lineError wraps the error of parsing the n-th line of the corpus.
+// The kind and the path of err are kept.
+func lineError(op string, n int, model string, err error) *Error {
+	msg := err.Error()
+	e := &Error{Op: op}
+	if me, ok := err.(*Error); ok {
+		msg = me.err
+		e.Path = me.Path
+		e.Kind = me.Kind
+	}
+	e.err = fmt.Sprintf("line %d: %s: %s", n, model, msg)
+	return e
+}
+
+// diffTokens aligns the tokens, and returns the groups that differ.
+func (d *ModelDiffer) diffTokens(before, after []Token) []TokenChange {
+	var changes []TokenChange
+	alignTokens(before, after, func(a, b []Token) {
+		var kind ChangeKind
+		switch {
+		case len(a) == 1 && len(b) == 1:
+			if d.feature(a[0].Feature) == d.feature(b[0].Feature) {
+				return
+			}
+			kind = ChangePOS
+		case len(a) == 1:
+			kind = ChangeSplit
+		case len(b) == 1:
+			kind = ChangeMerge
+		default:
+			kind = ChangeResegment
+		}
+		changes = append(changes, TokenChange{
+			Kind: kind,
+			Old:  a,
+			New:  b,
+		})
+	})
+	return changes
+}
+
+// feature returns the compared part of the feature.
+func (d *ModelDiffer) feature(feature string) string {
+	if d.FeatureFields <= 0 {
+		return feature
+	}
+	return featurePrefix(feature, d.FeatureFields)
+}
+
+func (d *ModelDiffer) changeCount(c TokenChange) ChangeCount {
+	if c.Kind == ChangePOS {
+		return ChangeCount{
+			Kind: c.Kind,
+			Old:  d.feature(c.Old[0].Feature),
+			New:  d.feature(c.New[0].Feature),
+		}
+	}
+	return ChangeCount{
+		Kind: c.Kind,
+		Old:  joinSurfaces(c.Old),
+		New:  joinSurfaces(c.New),
+	}
+}
+
+func topChangeCounts(m map[ChangeCount]int, n int) []ChangeCount {
+	ret := make([]ChangeCount, 0, len(m))
+	for c, count := range m {
+		c.Count = count
+		ret = append(ret, c)
+	}
+	slices.SortFunc(ret, func(a, b ChangeCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Kind, b.Kind); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Old, b.Old); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.New, b.New)
+	})
+	if n >= 0 && len(ret) > n {
+		ret = ret[:n]
+	}
+	return ret
+}
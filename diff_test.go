@@ -0,0 +1,148 @@
+package mecab
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shogo82148/go-mecab/internal/dictest"
+)
+
+func TestModelDiffer_diffTokens(t *testing.T) {
+	tokens := func(morphs ...string) []Token {
+		var ret []Token
+		pos := 0
+		for i := 0; i < len(morphs); i += 2 {
+			s := morphs[i]
+			ret = append(ret, Token{Surface: s, Feature: morphs[i+1], Start: pos, End: pos + len(s)})
+			pos += len(s)
+		}
+		return ret
+	}
+	before := tokens("東京スカイツリー", "名詞,固有名詞", "に", "助詞,格助詞", "行く", "動詞,自立", "。", "記号,句点")
+	after := tokens("東京", "名詞,固有名詞", "スカイツリー", "名詞,固有名詞", "に", "助詞,副詞化", "行く", "動詞,自立", "。", "記号,一般")
+
+	var d ModelDiffer
+	var got []ChangeCount
+	for _, c := range d.diffTokens(before, after) {
+		got = append(got, d.changeCount(c))
+	}
+	want := []ChangeCount{
+		{Kind: ChangeSplit, Old: "東京スカイツリー", New: "東京 スカイツリー"},
+		{Kind: ChangePOS, Old: "助詞,格助詞", New: "助詞,副詞化"},
+		{Kind: ChangePOS, Old: "記号,句点", New: "記号,一般"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%d: want %v, got %v", i, want[i], got[i])
+		}
+	}
+
+	// compare only the part-of-speech.
+	d = ModelDiffer{FeatureFields: 1}
+	if changes := d.diffTokens(before, after); len(changes) != 1 || changes[0].Kind != ChangeSplit {
+		t.Errorf("want only the split, got %v", changes)
+	}
+
+	// the merge and the resegmentation.
+	changes := d.diffTokens(after[:2], before[:1])
+	if len(changes) != 1 || changes[0].Kind != ChangeMerge {
+		t.Errorf("want the merge, got %v", changes)
+	}
+	changes = d.diffTokens(tokens("に", "助詞", "行く", "動詞"), tokens("に行", "動詞", "く", "動詞"))
+	if len(changes) != 1 || changes[0].Kind != ChangeResegment {
+		t.Errorf("want the resegmentation, got %v", changes)
+	}
+}
+
+func TestModelDiff_WriteJSON(t *testing.T) {
+	d := &ModelDiff{
+		Sentences: 2,
+		Diffs: []SentenceDiff{
+			{Line: 1, Sentence: "東京", Old: []Token{{Surface: "東京", PosID: 38, LCAttr: 1285}}},
+		},
+		Summary: []ChangeCount{
+			{Kind: ChangeMerge, Old: "東京 都", New: "東京都", Count: 3},
+		},
+	}
+	var buf bytes.Buffer
+	if err := d.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got ModelDiff
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Sentences != 2 || len(got.Summary) != 1 || got.Summary[0] != d.Summary[0] {
+		t.Errorf("want %v, got %v", d, got)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"kind": "merge"`)) {
+		t.Errorf("want the kind in text, got %s", buf.String())
+	}
+	for _, key := range []string{`"surface": "東京"`, `"pos_id": 38`, `"lc_attr": 1285`} {
+		if !bytes.Contains(buf.Bytes(), []byte(key)) {
+			t.Errorf("want %s, got %s", key, buf.String())
+		}
+	}
+}
+
+func TestLineError(t *testing.T) {
+	err := lineError("DiffModels", 3, "old model", classifyError("ParseToNode", "something wrong", nil))
+	if want := "mecab: DiffModels: line 3: old model: something wrong"; err.Error() != want {
+		t.Errorf("want %q, got %q", want, err.Error())
+	}
+	if !errors.Is(err, ErrParseFailed) {
+		t.Errorf("want ErrParseFailed, got %v", err)
+	}
+}
+
+// TestDiffModels compares the test dictionary with and without a user dictionary.
+func TestDiffModels(t *testing.T) {
+	args := buildPureGoDictionary(t, "UTF-8")
+	before, err := NewModel(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer before.Destroy()
+
+	userdic := filepath.Join(t.TempDir(), "user.dic")
+	user := []dictest.Entry{
+		{Surface: "東京都", LCAttr: 1, RCAttr: 1, WCost: 100, Feature: "名詞,固有名詞,トーキョート"},
+	}
+	if err := os.WriteFile(userdic, dictest.Dictionary(1, 2, 2, "UTF-8", user), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args["userdic"] = userdic
+	after, err := NewModel(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer after.Destroy()
+
+	diff, err := DiffModels(before, after, strings.NewReader("東京都\n\n東京\n東京都 東京都\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.Sentences != 3 || len(diff.Diffs) != 2 || diff.Diffs[0].Line != 1 || diff.Diffs[1].Line != 4 {
+		t.Errorf("unexpected diffs: %v", diff)
+	}
+	want := ChangeCount{Kind: ChangeMerge, Old: "東京 都", New: "東京都", Count: 3}
+	if len(diff.Summary) != 1 || diff.Summary[0] != want {
+		t.Errorf("want %v, got %v", want, diff.Summary)
+	}
+	if len(diff.Diffs) > 0 {
+		got := diff.Diffs[0]
+		if len(got.Changes) != 1 || got.Changes[0].Kind != ChangeMerge ||
+			len(got.Changes[0].Old) != 2 || len(got.Changes[0].New) != 1 || got.Changes[0].New[0].Surface != "東京都" {
+			t.Errorf("unexpected changes: %v", got.Changes)
+		}
+	}
+}
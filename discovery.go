@@ -0,0 +1,237 @@
+package mecab
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DictionaryLocation is the location of a dictionary found by [DiscoverDictionary].
+type DictionaryLocation struct {
+	// Dir is the directory of the dictionary, which contains sys.dic.
+	Dir string
+
+	// Source describes how the dictionary is found.
+	// It is the name of the environment value, "mecab-config" or "default path".
+	Source string
+}
+
+// DicdirEnvs is the environment values that [DiscoverDictionary] checks.
+var DicdirEnvs = []string{"MECAB_DICDIR"}
+
+// DefaultDicdirs is the common install paths of dictionaries that [DiscoverDictionary] checks.
+var DefaultDicdirs = []string{
+	"/usr/local/lib/mecab/dic",
+	"/usr/lib/mecab/dic",
+	"/usr/lib/x86_64-linux-gnu/mecab/dic",
+	"/usr/lib/aarch64-linux-gnu/mecab/dic",
+	"/var/lib/mecab/dic",
+	"/usr/share/mecab/dic",
+	"/opt/homebrew/lib/mecab/dic",
+	"/opt/local/lib/mecab/dic",
+}
+
+// preferredDictionaries is the preferred order of the dictionaries
+// when the directory contains several dictionaries.
+var preferredDictionaries = []string{
+	"ipadic",
+	"ipadic-utf8",
+	"debian",
+	"naist-jdic",
+	"unidic",
+	"jumandic",
+}
+
+// mecabConfigDicdir returns the output of "mecab-config --dicdir".
+func mecabConfigDicdir() (string, error) {
+	return mecabConfig("--dicdir")
+}
+
+// mecabConfig returns the output of mecab-config with the option.
+func mecabConfig(option string) (string, error) {
+	path, err := exec.LookPath("mecab-config")
+	if err != nil {
+		return "", err
+	}
+	out, err := exec.Command(path, option).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// lookMeCabTool returns the path of the command of MeCab, e.g. mecab-cost-train.
+// The commands are installed in the libexec directory, so it is also checked
+// in addition to PATH.
+func lookMeCabTool(name string) (string, error) {
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+	dir, err := mecabConfig("--libexecdir")
+	if err != nil {
+		return "", fmt.Errorf("%s is not found in PATH, and mecab-config is not available", name)
+	}
+	return exec.LookPath(filepath.Join(dir, name))
+}
+
+// discovery is the search paths of [DiscoverDictionary].
+type discovery struct {
+	envs         []string
+	lookupEnv    func(key string) (string, bool)
+	configDicdir func() (string, error)
+	dicdirs      []string
+}
+
+// DiscoverDictionary finds a dictionary without mecabrc.
+// It checks the following locations in order:
+//
+//   - the environment values in [DicdirEnvs]
+//   - the output of "mecab-config --dicdir"
+//   - the common install paths in [DefaultDicdirs]
+//
+// If a location contains several dictionaries, such as /usr/local/lib/mecab/dic/ipadic,
+// ipadic is preferred.
+func DiscoverDictionary() (DictionaryLocation, error) {
+	d := discovery{
+		envs:         DicdirEnvs,
+		lookupEnv:    os.LookupEnv,
+		configDicdir: mecabConfigDicdir,
+		dicdirs:      DefaultDicdirs,
+	}
+	return d.discover()
+}
+
+func (d discovery) discover() (DictionaryLocation, error) {
+	for _, env := range d.envs {
+		dir, ok := d.lookupEnv(env)
+		if !ok || dir == "" {
+			continue
+		}
+		if found, ok := findDictionary(dir); ok {
+			return DictionaryLocation{Dir: found, Source: env}, nil
+		}
+	}
+
+	if dir, err := d.configDicdir(); err == nil && dir != "" {
+		if found, ok := findDictionary(dir); ok {
+			return DictionaryLocation{Dir: found, Source: "mecab-config"}, nil
+		}
+	}
+
+	for _, dir := range d.dicdirs {
+		if found, ok := findDictionary(dir); ok {
+			return DictionaryLocation{Dir: found, Source: "default path"}, nil
+		}
+	}
+
+	return DictionaryLocation{}, &Error{
+		Op:   "DiscoverDictionary",
+		Kind: KindDictionaryNotFound,
+		err:  "no dictionary is found",
+	}
+}
+
+// findDictionary returns dir if it is a dictionary,
+// or a dictionary in the sub directories of dir.
+func findDictionary(dir string) (string, bool) {
+	if isDictionary(dir) {
+		return dir, true
+	}
+
+	for _, name := range preferredDictionaries {
+		sub := filepath.Join(dir, name)
+		if isDictionary(sub) {
+			return sub, true
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sub := filepath.Join(dir, name)
+		if isDictionary(sub) {
+			return sub, true
+		}
+	}
+	return "", false
+}
+
+func isDictionary(dir string) bool {
+	stat, err := os.Stat(filepath.Join(dir, "sys.dic"))
+	return err == nil && stat.Mode().IsRegular()
+}
+
+// NewFromDicdir returns a new MeCab parser that uses the dictionary in dicdir.
+// It doesn't need mecabrc. If dicdir is empty, [DiscoverDictionary] finds it.
+func NewFromDicdir(dicdir string, args map[string]string) (MeCab, error) {
+	var m MeCab
+	err := withGeneratedRC("New", dicdir, args, func(args map[string]string) error {
+		var err error
+		m, err = New(args)
+		return err
+	})
+	return m, err
+}
+
+// NewModelFromDicdir returns a new model that uses the dictionary in dicdir.
+// It doesn't need mecabrc. If dicdir is empty, [DiscoverDictionary] finds it.
+func NewModelFromDicdir(dicdir string, args map[string]string) (Model, error) {
+	var m Model
+	err := withGeneratedRC("NewModel", dicdir, args, func(args map[string]string) error {
+		var err error
+		m, err = NewModel(args)
+		return err
+	})
+	return m, err
+}
+
+// withGeneratedRC generates a temporary rcfile for dicdir, and calls f with it.
+// MeCab reads the rcfile only on initialization, so it is removed after f returns.
+func withGeneratedRC(op, dicdir string, args map[string]string, f func(args map[string]string) error) error {
+	if dicdir == "" {
+		loc, err := DiscoverDictionary()
+		if err != nil {
+			return err
+		}
+		dicdir = loc.Dir
+	}
+	if !isDictionary(dicdir) {
+		return &Error{
+			Op:   op,
+			Path: dicdir,
+			Kind: KindDictionaryNotFound,
+			err:  "no such dictionary: " + dicdir,
+		}
+	}
+
+	rc, err := os.CreateTemp("", "mecabrc-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(rc.Name())
+	_, err = rc.WriteString("dicdir = " + dicdir + "\n")
+	if err1 := rc.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+
+	newArgs := make(map[string]string, len(args)+2)
+	for k, v := range args {
+		newArgs[k] = v
+	}
+	newArgs["rcfile"] = rc.Name()
+	newArgs["dicdir"] = dicdir
+	return f(newArgs)
+}
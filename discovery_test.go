@@ -0,0 +1,125 @@
+package mecab
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func makeDictionary(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sys.dic"), []byte{}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// stubDiscovery returns the search paths with the environment values and the output of mecab-config.
+func stubDiscovery(env map[string]string, configDicdir string, dicdirs []string) discovery {
+	return discovery{
+		envs: DicdirEnvs,
+		lookupEnv: func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		},
+		configDicdir: func() (string, error) {
+			if configDicdir == "" {
+				return "", errors.New("mecab-config is not found")
+			}
+			return configDicdir, nil
+		},
+		dicdirs: dicdirs,
+	}
+}
+
+func TestDiscoverDictionary(t *testing.T) {
+	root := t.TempDir()
+	envDir := filepath.Join(root, "env")
+	configDir := filepath.Join(root, "config")
+	defaultDir := filepath.Join(root, "default")
+	makeDictionary(t, envDir)
+	makeDictionary(t, filepath.Join(configDir, "unidic"))
+	makeDictionary(t, filepath.Join(configDir, "ipadic"))
+	makeDictionary(t, filepath.Join(defaultDir, "foo"))
+
+	t.Run("env", func(t *testing.T) {
+		d := stubDiscovery(map[string]string{"MECAB_DICDIR": envDir}, configDir, []string{defaultDir})
+		loc, err := d.discover()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := DictionaryLocation{Dir: envDir, Source: "MECAB_DICDIR"}
+		if loc != want {
+			t.Errorf("want %v, got %v", want, loc)
+		}
+	})
+
+	t.Run("mecab-config", func(t *testing.T) {
+		d := stubDiscovery(map[string]string{"MECAB_DICDIR": filepath.Join(root, "missing")}, configDir, []string{defaultDir})
+		loc, err := d.discover()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := DictionaryLocation{Dir: filepath.Join(configDir, "ipadic"), Source: "mecab-config"}
+		if loc != want {
+			t.Errorf("want %v, got %v", want, loc)
+		}
+	})
+
+	t.Run("default path", func(t *testing.T) {
+		d := stubDiscovery(nil, "", []string{filepath.Join(root, "missing"), defaultDir})
+		loc, err := d.discover()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := DictionaryLocation{Dir: filepath.Join(defaultDir, "foo"), Source: "default path"}
+		if loc != want {
+			t.Errorf("want %v, got %v", want, loc)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		d := stubDiscovery(nil, "", nil)
+		_, err := d.discover()
+		if !errors.Is(err, ErrDictionaryNotFound) {
+			t.Errorf("want ErrDictionaryNotFound, got %v", err)
+		}
+	})
+}
+
+func TestNewModelFromDicdir(t *testing.T) {
+	loc, err := DiscoverDictionary()
+	if err != nil {
+		t.Skip("no dictionary is found")
+	}
+
+	model, err := NewModelFromDicdir(loc.Dir, map[string]string{
+		"output-format-type": "wakati",
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer model.Destroy()
+
+	mecab, err := model.NewMeCab()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer mecab.Destroy()
+
+	if _, err := mecab.Parse("こんにちは世界"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewModelFromDicdir_error(t *testing.T) {
+	_, err := NewModelFromDicdir(t.TempDir(), map[string]string{})
+	if !errors.Is(err, ErrDictionaryNotFound) {
+		t.Errorf("want ErrDictionaryNotFound, got %v", err)
+	}
+}
@@ -1,23 +1,189 @@
 package mecab
 
-// #include <mecab.h>
-import "C"
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shogo82148/go-mecab/internal/viterbi"
+)
+
+// ErrorKind is a category of errors of MeCab.
+type ErrorKind int
+
+const (
+	// KindUnknown is for errors that can't be categorized.
+	KindUnknown ErrorKind = iota
+
+	// KindDictionaryNotFound means that a file of the dictionary is not found.
+	KindDictionaryNotFound
+
+	// KindRCFileNotFound means that the resource file (mecabrc) is not found.
+	KindRCFileNotFound
+
+	// KindInvalidOption means that an option is unknown or has an invalid value.
+	KindInvalidOption
+
+	// KindCharsetMismatch means that the charsets of the dictionaries don't match.
+	KindCharsetMismatch
+
+	// KindParseFailed means that MeCab failed to parse the sentence.
+	KindParseFailed
+
+	// KindInputTooLong means that the input is longer than the input buffer.
+	KindInputTooLong
+
+	// KindInvalidInput means that the input contains NUL bytes or invalid UTF-8 sequences.
+	KindInvalidInput
+)
+
+func (kind ErrorKind) String() string {
+	switch kind {
+	case KindUnknown:
+		return "Unknown"
+	case KindDictionaryNotFound:
+		return "DictionaryNotFound"
+	case KindRCFileNotFound:
+		return "RCFileNotFound"
+	case KindInvalidOption:
+		return "InvalidOption"
+	case KindCharsetMismatch:
+		return "CharsetMismatch"
+	case KindParseFailed:
+		return "ParseFailed"
+	case KindInputTooLong:
+		return "InputTooLong"
+	case KindInvalidInput:
+		return "InvalidInput"
+	}
+	return ""
+}
+
+// The sentinel errors for each [ErrorKind].
+// [Error] matches them with [errors.Is].
+var (
+	ErrDictionaryNotFound = errors.New("mecab: dictionary not found")
+	ErrRCFileNotFound     = errors.New("mecab: rcfile not found")
+	ErrInvalidOption      = errors.New("mecab: invalid option")
+	ErrCharsetMismatch    = errors.New("mecab: charset mismatch")
+	ErrParseFailed        = errors.New("mecab: parse failed")
+	ErrInputTooLong       = errors.New("mecab: input is too long")
+	ErrInvalidInput       = errors.New("mecab: invalid input")
+)
+
+func (kind ErrorKind) sentinel() error {
+	switch kind {
+	case KindDictionaryNotFound:
+		return ErrDictionaryNotFound
+	case KindRCFileNotFound:
+		return ErrRCFileNotFound
+	case KindInvalidOption:
+		return ErrInvalidOption
+	case KindCharsetMismatch:
+		return ErrCharsetMismatch
+	case KindParseFailed:
+		return ErrParseFailed
+	case KindInputTooLong:
+		return ErrInputTooLong
+	case KindInvalidInput:
+		return ErrInvalidInput
+	}
+	return nil
+}
 
 // Error is an error of MeCab.
 type Error struct {
+	// Op is the operation that caused the error, e.g. "New", "NewModel" or "Parse".
+	Op string
+
+	// Path is the file that caused the error, if any.
+	Path string
+
+	// Kind is the category of the error.
+	Kind ErrorKind
+
 	err string
 }
 
 func (e *Error) Error() string {
-	return e.err
+	if e.Op == "" {
+		return e.err
+	}
+	return "mecab: " + e.Op + ": " + e.err
+}
+
+// Is reports whether the error matches target.
+// target is the sentinel error of e.Kind, such as [ErrDictionaryNotFound].
+func (e *Error) Is(target error) bool {
+	sentinel := e.Kind.sentinel()
+	return sentinel != nil && sentinel == target
+}
+
+// Configuration reports whether the error is caused by the configuration,
+// e.g. missing dictionaries or invalid options.
+// Retrying the operation with the same configuration doesn't help.
+func (e *Error) Configuration() bool {
+	switch e.Kind {
+	case KindDictionaryNotFound, KindRCFileNotFound, KindInvalidOption, KindCharsetMismatch:
+		return true
+	}
+	return false
 }
 
-func newError(m *C.mecab_t) error {
-	err := C.GoString(C.mecab_strerror(m))
-	if err == "" {
-		return nil
+// classifyError builds an Error from the message of MeCab.
+// args are the options of the operation, and they are used to find the resource file.
+// The message decides the kind first, and op is used only if the message is not recognized.
+func classifyError(op, msg string, args map[string]string) *Error {
+	e := &Error{
+		Op:  op,
+		err: msg,
+	}
+
+	for _, prefix := range []string{"no such file or directory: ", "open failed: "} {
+		if i := strings.LastIndex(msg, prefix); i >= 0 {
+			path := strings.TrimSpace(msg[i+len(prefix):])
+			e.Path = path
+			if slices.Contains(rcFiles(args), path) {
+				e.Kind = KindRCFileNotFound
+			} else {
+				e.Kind = KindDictionaryNotFound
+			}
+			return e
+		}
+	}
+
+	switch {
+	case strings.Contains(msg, "charset"):
+		e.Kind = KindCharsetMismatch
+	case strings.Contains(msg, "unrecognized option"),
+		strings.Contains(msg, "requires an argument"),
+		strings.Contains(msg, "doesn't allow an argument"),
+		strings.Contains(msg, "unknown format type"),
+		strings.Contains(msg, "invalid argument"):
+		e.Kind = KindInvalidOption
+	default:
+		switch op {
+		case "Parse", "ParseToString", "ParseToNode", "ParseLattice":
+			e.Kind = KindParseFailed
+		}
+	}
+	return e
+}
+
+// rcFiles returns the resource files that MeCab may load with args.
+// They are the rcfile option, MECABRC or the default locations, in this order of priority.
+func rcFiles(args map[string]string) []string {
+	if rc := args["rcfile"]; rc != "" {
+		return []string{rc}
+	}
+	if rc := os.Getenv("MECABRC"); rc != "" {
+		return []string{rc}
 	}
-	return &Error{
-		err: err,
+	files := slices.Clone(viterbi.DefaultRCFiles)
+	if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, ".mecabrc"))
 	}
+	return files
 }
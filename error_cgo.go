@@ -0,0 +1,26 @@
+//go:build cgo && !purego
+
+package mecab
+
+// #include <mecab.h>
+import "C"
+
+// newError returns the error of MeCab.
+// args are the options of the operation, and they are used to find the resource file.
+func newError(op string, m *C.mecab_t, args map[string]string) error {
+	err := C.GoString(C.mecab_strerror(m))
+	if err == "" {
+		return nil
+	}
+	return classifyError(op, err, args)
+}
+
+// newLatticeError returns the error set in the lattice.
+// It falls back to the error of MeCab if the lattice has no error.
+func newLatticeError(op string, l *C.mecab_lattice_t, m *C.mecab_t) error {
+	err := C.GoString(C.mecab_lattice_strerror(l))
+	if err == "" {
+		return newError(op, m, nil)
+	}
+	return classifyError(op, err, nil)
+}
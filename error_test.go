@@ -0,0 +1,126 @@
+package mecab
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Setenv("MECABRC", "")
+	tests := []struct {
+		op   string
+		msg  string
+		args map[string]string
+		kind ErrorKind
+		path string
+	}{
+		{
+			op:   "New",
+			msg:  "param.cpp(69) [ifs] no such file or directory: /usr/local/etc/mecabrc",
+			kind: KindRCFileNotFound,
+			path: "/usr/local/etc/mecabrc",
+		},
+		{
+			op:   "NewModel",
+			msg:  "tagger.cpp(151) [load_dictionary_resource(param)] param.cpp(69) [ifs] no such file or directory: /path/to/ipadic/dicrc",
+			kind: KindDictionaryNotFound,
+			path: "/path/to/ipadic/dicrc",
+		},
+		{
+			op:   "New",
+			msg:  "tokenizer.cpp(127) [sysdic->open(create_filename(prefix, SYS_DIC_FILE).c_str())] dictionary.cpp(59) [dmmap_->open(file, mode)] mmap.h(191) [(fd = ::open(filename, flag | O_BINARY)) >= 0] open failed: /path/to/ipadic/sys.dic",
+			kind: KindDictionaryNotFound,
+			path: "/path/to/ipadic/sys.dic",
+		},
+		{
+			op:   "New",
+			msg:  "writer.cpp(63) [*str] unknown format type [unknown format]",
+			kind: KindInvalidOption,
+		},
+		{
+			op:   "New",
+			msg:  "param.cpp(146) [unrecognized option `--foo`]",
+			kind: KindInvalidOption,
+		},
+		{
+			op:   "NewModel",
+			msg:  "tokenizer.cpp(140) [std::strcmp(sysdic->charset(), d->charset()) == 0] incompatible charset: EUC-JP != UTF-8",
+			kind: KindCharsetMismatch,
+		},
+		{
+			op:   "ParseLattice",
+			msg:  "tagger.cpp(789) [lattice->sentence()] sentence is NULL",
+			kind: KindParseFailed,
+		},
+		{
+			op:   "New",
+			msg:  "param.cpp(69) [ifs] no such file or directory: /path/to/mecabrc",
+			args: map[string]string{"rcfile": "/path/to/mecabrc"},
+			kind: KindRCFileNotFound,
+			path: "/path/to/mecabrc",
+		},
+		{
+			// the default rcfile is not loaded if the rcfile option is given.
+			op:   "New",
+			msg:  "param.cpp(69) [ifs] no such file or directory: /usr/local/etc/mecabrc",
+			args: map[string]string{"rcfile": "/path/to/mecabrc"},
+			kind: KindDictionaryNotFound,
+			path: "/usr/local/etc/mecabrc",
+		},
+		{
+			op:   "New",
+			msg:  "param.cpp(69) [ifs] no such file or directory: /path/to/ipadic/char.conf",
+			kind: KindDictionaryNotFound,
+			path: "/path/to/ipadic/char.conf",
+		},
+		{
+			op:   "ParseLattice",
+			msg:  "tokenizer.cpp(140) [std::strcmp(sysdic->charset(), d->charset()) == 0] incompatible charset: EUC-JP != UTF-8",
+			kind: KindCharsetMismatch,
+		},
+		{
+			op:   "Parse",
+			msg:  "something wrong",
+			kind: KindParseFailed,
+		},
+		{
+			op:   "Swap",
+			msg:  "something wrong",
+			kind: KindUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		err := classifyError(tt.op, tt.msg, tt.args)
+		if err.Op != tt.op {
+			t.Errorf("%q: want op %q, got %q", tt.msg, tt.op, err.Op)
+		}
+		if err.Kind != tt.kind {
+			t.Errorf("%q: want kind %s, got %s", tt.msg, tt.kind, err.Kind)
+		}
+		if err.Path != tt.path {
+			t.Errorf("%q: want path %q, got %q", tt.msg, tt.path, err.Path)
+		}
+		if tt.kind != KindUnknown && !errors.Is(err, tt.kind.sentinel()) {
+			t.Errorf("%q: want errors.Is(err, %v), but not", tt.msg, tt.kind.sentinel())
+		}
+	}
+}
+
+func TestError_As(t *testing.T) {
+	t.Setenv("MECABRC", "")
+	var err error = classifyError("New", "param.cpp(69) [ifs] no such file or directory: /etc/mecabrc", nil)
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatal("want *Error, but not")
+	}
+	if !e.Configuration() {
+		t.Error("want configuration error, but not")
+	}
+	if errors.Is(err, ErrDictionaryNotFound) {
+		t.Error("want not ErrDictionaryNotFound, but it is")
+	}
+	if want := "mecab: New: param.cpp(69) [ifs] no such file or directory: /etc/mecabrc"; err.Error() != want {
+		t.Errorf("want %q, got %q", want, err.Error())
+	}
+}
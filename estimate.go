@@ -0,0 +1,194 @@
+package mecab
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CostEstimator assigns the context IDs and the costs of user dictionary entries,
+// as mecab-dict-index -a -m model does.
+// The context IDs are decided by the rewrite rules of the dictionary,
+// and the costs are estimated from the features by the trained model.
+type CostEstimator struct {
+	// Dicdir is the source directory of the system dictionary.
+	// It must contain dicrc, matrix.def or matrix.bin, left-id.def, right-id.def and rewrite.def.
+	Dicdir string
+
+	// Model is the trained model file.
+	// If it is empty, model.def in Dicdir is used.
+	Model string
+
+	// Charset is the charset of the dictionary and the model.
+	// If it is empty, the charset of sys.dic in Dicdir is used, or UTF-8 if Dicdir doesn't have sys.dic.
+	Charset string
+}
+
+// EstimateUserEntry returns a new user dictionary entry with the context IDs and the cost assigned.
+// It is a shortcut of [CostEstimator.Estimate] for one entry.
+func (e *CostEstimator) EstimateUserEntry(surface string, features ...string) (UserEntry, error) {
+	entries, err := e.Estimate([]UserEntry{{
+		Surface:  surface,
+		Features: features,
+	}})
+	if err != nil {
+		return UserEntry{}, err
+	}
+	return entries[0], nil
+}
+
+// Estimate returns a copy of the entries with the context IDs and the costs assigned.
+// LeftID, RightID and Cost of the entries are ignored, and Auto of the results is false.
+// The entries are validated before estimating, and the errors are reported as [*UserDictionaryError].
+func (e *CostEstimator) Estimate(entries []UserEntry) ([]UserEntry, error) {
+	const op = "EstimateCost"
+
+	var errs []*UserEntryError
+	lines := make([]userDictionaryLine, len(entries))
+	for i, entry := range entries {
+		if err := validateUserWord(entry); err != nil {
+			errs = append(errs, &UserEntryError{Line: i + 1, Err: err})
+		}
+		lines[i] = userDictionaryLine{
+			line:  i + 1,
+			entry: entry,
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &UserDictionaryError{Errors: errs}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	// MeCab terminates the process if the files are missing,
+	// so they must be checked here.
+	model := e.Model
+	if model == "" {
+		model = filepath.Join(e.Dicdir, "model.def")
+	}
+	err := checkDictIndexFiles(op,
+		[]string{filepath.Join(e.Dicdir, "dicrc")},
+		[]string{filepath.Join(e.Dicdir, "matrix.bin"), filepath.Join(e.Dicdir, "matrix.def")},
+		[]string{filepath.Join(e.Dicdir, "left-id.def")},
+		[]string{filepath.Join(e.Dicdir, "right-id.def")},
+		[]string{filepath.Join(e.Dicdir, "rewrite.def")},
+		[]string{model},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	charset := e.Charset
+	if charset == "" {
+		charset = "UTF-8"
+		if header, err := readSystemDictionaryHeader(filepath.Join(e.Dicdir, "sys.dic")); err == nil {
+			charset = header.charset
+		}
+	}
+	c, err := newCodec(op, charset)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := writeUserDictionaryCSV(lines, c)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(input)
+
+	f, err := os.CreateTemp("", "mecab-userdic-*.csv")
+	if err != nil {
+		return nil, err
+	}
+	output := f.Name()
+	f.Close()
+	defer os.Remove(output)
+
+	err = dictIndex(op, output, []string{
+		"mecab-dict-index",
+		"--dicdir=" + e.Dicdir,
+		"--model=" + model,
+		"--userdic=" + output,
+		"--dictionary-charset=" + charset,
+		"--charset=" + charset,
+		"--assign-user-dictionary-costs",
+		input,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		return nil, err
+	}
+	text := string(data)
+	if c != nil {
+		text = c.decode(text)
+	}
+	return parseEstimatedEntries(op, text, entries)
+}
+
+// parseEstimatedEntries parses the output of mecab-dict-index -a for entries.
+// mecab-dict-index writes the surfaces without quotes, so the surfaces with commas can't be split as CSV.
+// The lines are matched with the surfaces of entries in order instead,
+// and the features of entries are kept.
+func parseEstimatedEntries(op, text string, entries []UserEntry) ([]UserEntry, error) {
+	ret := make([]UserEntry, 0, len(entries))
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			continue
+		}
+		if len(ret) >= len(entries) {
+			return nil, &Error{
+				Op:  op,
+				err: fmt.Sprintf("unexpected output of mecab-dict-index: want %d entries, got more", len(entries)),
+			}
+		}
+		entry, err := parseEstimatedEntry(line, entries[len(ret)])
+		if err != nil {
+			return nil, &Error{
+				Op:  op,
+				err: fmt.Sprintf("unexpected output of mecab-dict-index: %v", err),
+			}
+		}
+		ret = append(ret, entry)
+	}
+	if len(ret) != len(entries) {
+		return nil, &Error{
+			Op:  op,
+			err: fmt.Sprintf("unexpected output of mecab-dict-index: want %d entries, got %d", len(entries), len(ret)),
+		}
+	}
+	return ret, nil
+}
+
+// parseEstimatedEntry parses a line "surface,left-id,right-id,cost,features" for entry.
+func parseEstimatedEntry(line string, entry UserEntry) (UserEntry, error) {
+	rest, ok := strings.CutPrefix(line, entry.Surface+",")
+	if !ok {
+		// the surface may be quoted, as it is written in the input.
+		rest, ok = strings.CutPrefix(line, JoinFeature([]string{entry.Surface})+",")
+	}
+	if !ok {
+		return UserEntry{}, fmt.Errorf("want the surface %q, got %q", entry.Surface, line)
+	}
+	fields := strings.SplitN(rest, ",", 4)
+	if len(fields) < 3 {
+		return UserEntry{}, fmt.Errorf("the costs are not assigned: %q", line)
+	}
+	var ids [3]int
+	for i, name := range []string{"left-id", "right-id", "cost"} {
+		v, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return UserEntry{}, fmt.Errorf("invalid %s %q", name, fields[i])
+		}
+		ids[i] = v
+	}
+	entry.LeftID, entry.RightID, entry.Cost, entry.Auto = ids[0], ids[1], ids[2], false
+	return entry, nil
+}
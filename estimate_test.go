@@ -0,0 +1,134 @@
+package mecab
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCostEstimator_invalid(t *testing.T) {
+	e := &CostEstimator{Dicdir: t.TempDir()}
+	_, err := e.Estimate([]UserEntry{
+		{Surface: "東京スカイツリー", Features: []string{"名詞"}},
+		{Surface: "", Features: []string{"名詞"}},
+	})
+	var dicErr *UserDictionaryError
+	if !errors.As(err, &dicErr) {
+		t.Fatalf("want UserDictionaryError, got %v", err)
+	}
+	if want := []int{2}; !slices.Equal(dicErr.Lines(), want) {
+		t.Errorf("want lines %v, got %v", want, dicErr.Lines())
+	}
+}
+
+func TestCostEstimator_noModel(t *testing.T) {
+	e := &CostEstimator{Dicdir: t.TempDir()}
+	_, err := e.EstimateUserEntry("東京スカイツリー", "名詞", "固有名詞")
+	if !errors.Is(err, ErrDictionaryNotFound) {
+		t.Errorf("want ErrDictionaryNotFound, got %v", err)
+	}
+}
+
+func TestParseEstimatedEntries(t *testing.T) {
+	input := []UserEntry{
+		{Surface: "東京スカイツリー", Features: []string{"名詞", "固有名詞"}, Auto: true},
+		{Surface: "a,b", Features: []string{"記号"}, Auto: true},
+		{Surface: `"c"`, Features: []string{"記号"}, Auto: true},
+	}
+	// mecab-dict-index writes the surfaces without quotes.
+	output := "東京スカイツリー,1288,1288,3967,名詞,固有名詞\na,b,1,2,3,記号\n\"c\",4,5,-6,記号\n"
+	entries, err := parseEstimatedEntries("EstimateCost", output, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []UserEntry{
+		{Surface: "東京スカイツリー", LeftID: 1288, RightID: 1288, Cost: 3967, Features: []string{"名詞", "固有名詞"}},
+		{Surface: "a,b", LeftID: 1, RightID: 2, Cost: 3, Features: []string{"記号"}},
+		{Surface: `"c"`, LeftID: 4, RightID: 5, Cost: -6, Features: []string{"記号"}},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("want %d entries, got %d", len(want), len(entries))
+	}
+	for i := range want {
+		if entries[i].Surface != want[i].Surface || entries[i].LeftID != want[i].LeftID ||
+			entries[i].RightID != want[i].RightID || entries[i].Cost != want[i].Cost ||
+			!slices.Equal(entries[i].Features, want[i].Features) || entries[i].Auto {
+			t.Errorf("%d: want %#v, got %#v", i, want[i], entries[i])
+		}
+	}
+
+	if _, err := parseEstimatedEntries("EstimateCost", "a,1,2,3,記号\n", input[:2]); err == nil {
+		t.Error("want error, got nil")
+	}
+	if _, err := parseEstimatedEntries("EstimateCost", "a,b,,,,記号\n", input[1:2]); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestUserDictionaryCompiler_noEstimator(t *testing.T) {
+	c := &UserDictionaryCompiler{Dicdir: fakeDicdir(t, 10, 10, "utf-8")}
+	output := filepath.Join(t.TempDir(), "user.dic")
+	csv := "東京スカイツリー,1,1,100,名詞\n" +
+		"ツリー,,,,名詞\n"
+	err := c.CompileCSV(output, strings.NewReader(csv))
+	var dicErr *UserDictionaryError
+	if !errors.As(err, &dicErr) {
+		t.Fatalf("want UserDictionaryError, got %v", err)
+	}
+	if want := []int{2}; !slices.Equal(dicErr.Lines(), want) {
+		t.Errorf("want lines %v, got %v", want, dicErr.Lines())
+	}
+}
+
+func TestCostEstimator(t *testing.T) {
+	dicdir := buildTestDictionary(t)
+	model := filepath.Join(t.TempDir(), "model")
+	tr := &Trainer{
+		Dicdir: dicdir,
+		Eta:    0.001,
+	}
+	if err := tr.Train(model, "testdata/corpus.txt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	e := &CostEstimator{
+		Dicdir: dicdir,
+		Model:  model,
+	}
+	// the features must have the contexts in the seed dictionary, and the surfaces start with the same character type.
+	entries, err := e.Estimate([]UserEntry{
+		{Surface: "ねこ", Features: []string{"名詞", "一般", "猫", "ネコ"}},
+		{Surface: "ね,こ", Features: []string{"名詞", "一般", "猫", "ネコ"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	left, err := readIDTable("test", filepath.Join(dicdir, "left-id.def"), false, "UTF-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	right, err := readIDTable("test", filepath.Join(dicdir, "right-id.def"), false, "UTF-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lid, _ := left.ID("名詞,一般,猫")
+	rid, _ := right.ID("名詞,一般,猫")
+	for _, entry := range entries {
+		if entry.Auto || entry.LeftID != lid || entry.RightID != rid {
+			t.Errorf("want the context IDs %d and %d, got %#v", lid, rid, entry)
+		}
+		if entry.Cost < math.MinInt16 || entry.Cost > math.MaxInt16 {
+			t.Errorf("cost is out of range: %#v", entry)
+		}
+	}
+	if entries[0].Surface != "ねこ" || entries[1].Surface != "ね,こ" {
+		t.Errorf("unexpected surfaces: %q, %q", entries[0].Surface, entries[1].Surface)
+	}
+	if entries[0].Cost != entries[1].Cost {
+		t.Errorf("want the same costs for the same features, got %d and %d", entries[0].Cost, entries[1].Cost)
+	}
+}
@@ -0,0 +1,315 @@
+package mecab
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// DefaultEvaluationLevels are the levels that [Evaluate] reports.
+// Level 0 compares only the segmentation,
+// and level n also compares the first n feature fields, e.g. the part-of-speech and its sub categories.
+var DefaultEvaluationLevels = []int{0, 1, 2, 3, 4}
+
+// Evaluator evaluates a tagger against a gold corpus, as mecab-system-eval does.
+//
+// The gold corpus is in the format of the output of MeCab: each line has a surface and the features
+// separated by a tab, and each sentence ends with "EOS".
+type Evaluator struct {
+	// Levels are the levels to report. If it is nil, [DefaultEvaluationLevels] is used.
+	Levels []int
+
+	// TopErrors is the number of the errors in the confusion report.
+	// If it is zero, 10 is used.
+	TopErrors int
+}
+
+// Evaluation is the result of the evaluation.
+type Evaluation struct {
+	// Sentences is the number of the sentences.
+	Sentences int
+
+	// Scores are the scores of each level.
+	Scores []LevelScore
+
+	// Confusions are the most frequent pairs of the gold features and the tagger features
+	// of the tokens that are segmented correctly, compared at the highest level.
+	Confusions []Confusion
+
+	// SegmentationErrors are the most frequent segmentation errors.
+	// Gold and System of them are the surfaces separated by spaces.
+	SegmentationErrors []Confusion
+}
+
+// LevelScore is the score of a level.
+type LevelScore struct {
+	// Level is the number of the compared feature fields. Level 0 compares only the segmentation.
+	Level int
+
+	// Gold is the number of the tokens in the gold corpus.
+	Gold int
+
+	// System is the number of the tokens of the tagger.
+	System int
+
+	// Correct is the number of the correct tokens.
+	Correct int
+}
+
+// Precision returns Correct / System.
+func (s LevelScore) Precision() float64 {
+	return ratio(s.Correct, s.System)
+}
+
+// Recall returns Correct / Gold.
+func (s LevelScore) Recall() float64 {
+	return ratio(s.Correct, s.Gold)
+}
+
+// F1 returns the harmonic mean of the precision and the recall.
+func (s LevelScore) F1() float64 {
+	p, r := s.Precision(), s.Recall()
+	if p+r == 0 {
+		return 0
+	}
+	return 2 * p * r / (p + r)
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// Confusion is a pair of the gold result and the tagger result.
+type Confusion struct {
+	Gold   string
+	System string
+	Count  int
+}
+
+// String returns the report in the format like mecab-system-eval.
+func (e *Evaluation) String() string {
+	var buf strings.Builder
+	buf.WriteString("              precision          recall         F\n")
+	for _, s := range e.Scores {
+		fmt.Fprintf(&buf, "LEVEL %d:    %.4f(%d/%d) %.4f(%d/%d) %.4f\n",
+			s.Level,
+			s.Precision()*100, s.Correct, s.System,
+			s.Recall()*100, s.Correct, s.Gold,
+			s.F1()*100)
+	}
+	if len(e.Confusions) > 0 {
+		buf.WriteString("\nconfusions (gold -> system):\n")
+		for _, c := range e.Confusions {
+			fmt.Fprintf(&buf, "%6d  %s -> %s\n", c.Count, c.Gold, c.System)
+		}
+	}
+	if len(e.SegmentationErrors) > 0 {
+		buf.WriteString("\nsegmentation errors (gold -> system):\n")
+		for _, c := range e.SegmentationErrors {
+			fmt.Fprintf(&buf, "%6d  %s -> %s\n", c.Count, c.Gold, c.System)
+		}
+	}
+	return buf.String()
+}
+
+// Evaluate evaluates the tagger against the gold corpus with the default settings.
+// It is a shortcut of [Evaluator.Evaluate].
+func Evaluate(tagger MeCab, gold io.Reader) (*Evaluation, error) {
+	var e Evaluator
+	return e.Evaluate(tagger, gold)
+}
+
+// Evaluate parses the sentences of the gold corpus with the tagger, and compares the results.
+func (e *Evaluator) Evaluate(tagger MeCab, gold io.Reader) (*Evaluation, error) {
+	const op = "Evaluate"
+
+	levels := e.Levels
+	if levels == nil {
+		levels = DefaultEvaluationLevels
+	}
+	maxLevel := slices.Max(append([]int{0}, levels...))
+	scores := make([]LevelScore, len(levels))
+	for i, level := range levels {
+		scores[i].Level = level
+	}
+	confusions := map[Confusion]int{}
+	segmentation := map[Confusion]int{}
+
+	sentences := 0
+	err := readGoldCorpus(op, gold, func(want []Token) error {
+		var text strings.Builder
+		for _, t := range want {
+			text.WriteString(t.Surface)
+		}
+		node, err := tagger.ParseToNode(text.String())
+		if err != nil {
+			return err
+		}
+		got := node.Tokens()
+		sentences++
+
+		for i := range scores {
+			scores[i].Gold += len(want)
+			scores[i].System += len(got)
+		}
+		alignTokens(want, got, func(want, got []Token) {
+			if len(want) == 1 && len(got) == 1 {
+				for i, level := range levels {
+					if featurePrefix(want[0].Feature, level) == featurePrefix(got[0].Feature, level) {
+						scores[i].Correct++
+					}
+				}
+				if maxLevel > 0 {
+					g, s := featurePrefix(want[0].Feature, maxLevel), featurePrefix(got[0].Feature, maxLevel)
+					if g != s {
+						confusions[Confusion{Gold: g, System: s}]++
+					}
+				}
+				return
+			}
+			segmentation[Confusion{Gold: joinSurfaces(want), System: joinSurfaces(got)}]++
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	top := e.TopErrors
+	if top == 0 {
+		top = 10
+	}
+	return &Evaluation{
+		Sentences:          sentences,
+		Scores:             scores,
+		Confusions:         topConfusions(confusions, top),
+		SegmentationErrors: topConfusions(segmentation, top),
+	}, nil
+}
+
+// readGoldCorpus reads the sentences of the corpus, and calls f with the tokens of each sentence.
+func readGoldCorpus(op string, r io.Reader, f func(tokens []Token) error) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var tokens []Token
+	pos := 0
+	n := 0
+	for s.Scan() {
+		n++
+		line := strings.TrimSuffix(s.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if line == "EOS" {
+			if len(tokens) > 0 {
+				if err := f(tokens); err != nil {
+					return err
+				}
+			}
+			tokens = tokens[:0]
+			pos = 0
+			continue
+		}
+		surface, feature, ok := strings.Cut(line, "\t")
+		if !ok || surface == "" {
+			return corpusError(op, "gold corpus", n, "want surface and feature separated by a tab")
+		}
+		tokens = append(tokens, Token{
+			Surface: surface,
+			Feature: feature,
+			Start:   pos,
+			End:     pos + len(surface),
+		})
+		pos += len(surface)
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	if len(tokens) > 0 {
+		return corpusError(op, "gold corpus", n, "the last sentence doesn't end with EOS")
+	}
+	return nil
+}
+
+// alignTokens splits the tokens into the smallest groups that have the same boundaries,
+// and calls f with each group.
+// The groups that have one token in both want and got are segmented correctly.
+func alignTokens(want, got []Token, f func(want, got []Token)) {
+	i, j := 0, 0
+	for i < len(want) || j < len(got) {
+		i0, j0 := i, j
+		if i < len(want) && j < len(got) && want[i].Start == got[j].Start && want[i].End == got[j].End {
+			f(want[i:i+1], got[j:j+1])
+			i++
+			j++
+			continue
+		}
+
+		// advance the one that ends earlier until both end at the same offset.
+		for {
+			if j >= len(got) || (i < len(want) && want[i].End < got[j].End) {
+				i++
+			} else if i >= len(want) || want[i].End > got[j].End {
+				j++
+			} else {
+				i++
+				j++
+				break
+			}
+			if i >= len(want) && j >= len(got) {
+				break
+			}
+		}
+		f(want[i0:i], got[j0:j])
+	}
+}
+
+// featurePrefix returns the first n fields of the feature.
+func featurePrefix(feature string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	fields := make([]string, 0, n)
+	for field := range FeatureFields(feature) {
+		fields = append(fields, field)
+		if len(fields) == n {
+			break
+		}
+	}
+	return JoinFeature(fields)
+}
+
+func joinSurfaces(tokens []Token) string {
+	surfaces := make([]string, len(tokens))
+	for i, t := range tokens {
+		surfaces[i] = t.Surface
+	}
+	return strings.Join(surfaces, " ")
+}
+
+func topConfusions(m map[Confusion]int, n int) []Confusion {
+	ret := make([]Confusion, 0, len(m))
+	for c, count := range m {
+		c.Count = count
+		ret = append(ret, c)
+	}
+	slices.SortFunc(ret, func(a, b Confusion) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Gold, b.Gold); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.System, b.System)
+	})
+	if len(ret) > n {
+		ret = ret[:n]
+	}
+	return ret
+}
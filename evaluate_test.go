@@ -0,0 +1,91 @@
+package mecab
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAlignTokens(t *testing.T) {
+	tokens := func(surfaces ...string) []Token {
+		var ret []Token
+		pos := 0
+		for _, s := range surfaces {
+			ret = append(ret, Token{Surface: s, Start: pos, End: pos + len(s)})
+			pos += len(s)
+		}
+		return ret
+	}
+	want := tokens("東京", "スカイツリー", "に", "行く")
+	got := tokens("東京", "スカイ", "ツリー", "に行", "く")
+
+	var groups []string
+	alignTokens(want, got, func(want, got []Token) {
+		groups = append(groups, joinSurfaces(want)+"/"+joinSurfaces(got))
+	})
+	expected := []string{"東京/東京", "スカイツリー/スカイ ツリー", "に 行く/に行 く"}
+	if !slices.Equal(groups, expected) {
+		t.Errorf("want %q, got %q", expected, groups)
+	}
+}
+
+func TestFeaturePrefix(t *testing.T) {
+	tests := []struct {
+		feature string
+		n       int
+		want    string
+	}{
+		{"名詞,固有名詞,一般", 0, ""},
+		{"名詞,固有名詞,一般", 2, "名詞,固有名詞"},
+		{"名詞,固有名詞,一般", 5, "名詞,固有名詞,一般"},
+		{`記号,"1,000"`, 2, `記号,"1,000"`},
+	}
+	for _, tt := range tests {
+		if got := featurePrefix(tt.feature, tt.n); got != tt.want {
+			t.Errorf("%q, %d: want %q, got %q", tt.feature, tt.n, tt.want, got)
+		}
+	}
+}
+
+func TestLevelScore(t *testing.T) {
+	s := LevelScore{Gold: 10, System: 8, Correct: 6}
+	if s.Precision() != 0.75 || s.Recall() != 0.6 {
+		t.Errorf("unexpected score: %v %v", s.Precision(), s.Recall())
+	}
+	if f := s.F1(); f < 0.666 || f > 0.667 {
+		t.Errorf("unexpected F1: %v", f)
+	}
+	if f := (LevelScore{}).F1(); f != 0 {
+		t.Errorf("want 0, got %v", f)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	dicdir := buildTestDictionary(t)
+	mecab, err := NewFromDicdir(dicdir, map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer mecab.Destroy()
+
+	gold, err := os.Open("testdata/corpus.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gold.Close()
+
+	e, err := Evaluate(mecab, gold)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Sentences != 4 {
+		t.Errorf("want 4 sentences, got %d", e.Sentences)
+	}
+	if len(e.Scores) != len(DefaultEvaluationLevels) || e.Scores[0].Gold != 27 {
+		t.Errorf("unexpected scores: %#v", e.Scores)
+	}
+	if !strings.HasPrefix(e.String(), "              precision") {
+		t.Errorf("unexpected report: %s", e.String())
+	}
+}
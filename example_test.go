@@ -142,3 +142,33 @@ func ExampleMeCab_ParseToNode() {
 	// 世界	名詞,一般,*,*,*,*,世界,セカイ,セカイ
 	// 	BOS/EOS,*,*,*,*,*,*,*,*
 }
+
+func ExampleLattice_Morphs() {
+	options := map[string]string{}
+	if path := os.Getenv("MECABRC_PATH"); path != "" {
+		options["rcfile"] = path
+	}
+
+	tagger, err := mecab.New(options)
+	if err != nil {
+		panic(err)
+	}
+	defer tagger.Destroy()
+
+	lattice, err := mecab.NewLattice()
+	if err != nil {
+		panic(err)
+	}
+	defer lattice.Destroy()
+
+	lattice.SetSentence("こんにちは世界")
+	if err := tagger.ParseLattice(lattice); err != nil {
+		panic(err)
+	}
+	for node := range lattice.Morphs() {
+		fmt.Printf("%s\t%s\n", node.Surface(), node.Feature())
+	}
+	// Output:
+	// こんにちは	感動詞,*,*,*,*,*,こんにちは,コンニチハ,コンニチワ
+	// 世界	名詞,一般,*,*,*,*,世界,セカイ,セカイ
+}
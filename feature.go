@@ -0,0 +1,133 @@
+package mecab
+
+import (
+	"iter"
+	"strings"
+)
+
+// FeatureFields returns an iterator over the fields of the feature string.
+// It follows the CSV rules of MeCab (tokenizeCSV):
+//
+//   - the spaces and tabs before a field are skipped.
+//   - a field may be quoted with double quotes to contain commas,
+//     and a double quote in a quoted field is escaped by another double quote.
+//     The characters between the closing double quote and the next comma are ignored.
+//   - a double quote that is not closed quotes the rest of the feature.
+//   - a trailing empty field is dropped, e.g. "a,b," has two fields.
+//
+// The fields are substrings of the feature, so they are not allocated
+// unless they contain escaped double quotes.
+func FeatureFields(feature string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		rest := feature
+		for rest != "" {
+			field, next, more := nextFeatureField(rest)
+			if !yield(field) || !more {
+				return
+			}
+			rest = next
+		}
+	}
+}
+
+// SplitFeature splits the feature string into the fields.
+// See [FeatureFields] for the rules.
+func SplitFeature(feature string) []string {
+	if feature == "" {
+		return nil
+	}
+	fields := make([]string, 0, strings.Count(feature, ",")+1)
+	for field := range FeatureFields(feature) {
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// nextFeatureField returns the first field of s, and the rest after the comma.
+// more is false if s has no more fields.
+func nextFeatureField(s string) (field, rest string, more bool) {
+	s = strings.TrimLeft(s, " \t")
+	if !strings.HasPrefix(s, `"`) {
+		i := strings.IndexByte(s, ',')
+		if i < 0 {
+			return s, "", false
+		}
+		return s[:i], s[i+1:], true
+	}
+
+	// quoted field
+	var buf []byte // it is used only if the field has escaped double quotes.
+	start := 1
+	for i := 1; i < len(s); i++ {
+		if s[i] != '"' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '"' {
+			// escaped double quote
+			buf = append(buf, s[start:i+1]...)
+			i++
+			start = i + 1
+			continue
+		}
+
+		// closing double quote
+		if buf == nil {
+			field = s[start:i]
+		} else {
+			field = string(append(buf, s[start:i]...))
+		}
+		// ignore the characters between the closing double quote and the comma, as MeCab does.
+		j := strings.IndexByte(s[i+1:], ',')
+		if j < 0 {
+			return field, "", false
+		}
+		return field, s[i+1+j+1:], true
+	}
+
+	// the double quote is not closed. the field continues to the end, as MeCab does.
+	if buf == nil {
+		return s[start:], "", false
+	}
+	return string(append(buf, s[start:]...)), "", false
+}
+
+// featureField returns the i-th field.
+// It returns an empty string if the field is missing or the placeholder "*".
+func featureField(fields []string, i int) string {
+	if i >= len(fields) {
+		return ""
+	}
+	if fields[i] == "*" {
+		return ""
+	}
+	return fields[i]
+}
+
+// JoinFeature joins the fields into a feature string.
+// It is the inverse of [SplitFeature]: the fields that contain commas or double quotes,
+// the fields that start with a space or a tab, and a trailing empty field are quoted.
+func JoinFeature(fields []string) string {
+	var buf strings.Builder
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if !needsQuote(field, i == len(fields)-1) {
+			buf.WriteString(field)
+			continue
+		}
+		buf.WriteByte('"')
+		buf.WriteString(strings.ReplaceAll(field, `"`, `""`))
+		buf.WriteByte('"')
+	}
+	return buf.String()
+}
+
+// needsQuote reports whether the field must be quoted to be split by [SplitFeature] as it is.
+func needsQuote(field string, last bool) bool {
+	if field == "" {
+		// a trailing empty field is dropped unless it is quoted.
+		return last
+	}
+	return strings.ContainsAny(field, `,"`) || field[0] == ' ' || field[0] == '\t'
+}
@@ -0,0 +1,82 @@
+package mecab
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitFeature(t *testing.T) {
+	tests := []struct {
+		feature string
+		want    []string
+	}{
+		{"", nil},
+		{"名詞,一般,*", []string{"名詞", "一般", "*"}},
+		// a trailing empty field is dropped, as tokenizeCSV of MeCab does.
+		{"a,,b,", []string{"a", "", "b"}},
+		{"a,", []string{"a"}},
+		{`a,""`, []string{"a", ""}},
+		{" a,\tb", []string{"a", "b"}},
+		{"a, ", []string{"a", ""}},
+		{`記号,"1,000",*`, []string{"記号", "1,000", "*"}},
+		{`"a""b",c`, []string{`a"b`, "c"}},
+		{`"""",""`, []string{`"`, ""}},
+		{`"a"x,b`, []string{"a", "b"}},
+		// the double quote that is not closed quotes the rest.
+		{`"a,b`, []string{"a,b"}},
+		{`x,"a""b,c`, []string{"x", `a"b,c`}},
+	}
+
+	for _, tt := range tests {
+		got := SplitFeature(tt.feature)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q: want %q, got %q", tt.feature, tt.want, got)
+		}
+	}
+}
+
+func TestFeatureFields_break(t *testing.T) {
+	var got []string
+	for field := range FeatureFields("a,b,c") {
+		got = append(got, field)
+		if field == "b" {
+			break
+		}
+	}
+	if want := []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestSplitFeature_quotedUniDic(t *testing.T) {
+	// the orthography contains a comma.
+	f := ParseUniDicFeature(`補助記号,読点,*,*,*,*,,"，","，",,"，",,記号,*,*,*,*,*,*,補助,,,,,*,*,*,6605693395456,24`)
+	if f.Orth != "，" || f.Goshu != "記号" || f.LemmaID != "24" {
+		t.Errorf("unexpected feature: %#v", f)
+	}
+}
+
+func BenchmarkFeatureFields(b *testing.B) {
+	feature := `名詞,普通名詞,一般,*,*,*,セカイ,世界,"世,界",セカイ,世界,セカイ,漢,*,*,*,*`
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for range FeatureFields(feature) {
+		}
+	}
+}
+
+func TestJoinFeature(t *testing.T) {
+	tests := [][]string{
+		{"名詞", "一般", "*"},
+		{"記号", "1,000", `"`, ""},
+		{`a"b`, ",", `""`},
+		{"a", ""},
+		{" a", "\tb", "c "},
+	}
+	for _, fields := range tests {
+		feature := JoinFeature(fields)
+		if got := SplitFeature(feature); !slices.Equal(got, fields) {
+			t.Errorf("%q: want %q, got %q", feature, fields, got)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !mecabdebug
+
+package mecab
+
+// usageGuard detects concurrent use of MeCab.
+// It does nothing unless the mecabdebug build tag is set.
+type usageGuard struct{}
+
+func (g *usageGuard) enter(op string) {}
+
+func (g *usageGuard) exit() {}
+
+func (g *usageGuard) nodeGuard() nodeGuard { return nodeGuard{} }
+
+// nodeGuard detects use of nodes that are invalidated by a new parse.
+// It does nothing unless the mecabdebug build tag is set.
+type nodeGuard struct{}
+
+func (g nodeGuard) check(m *mecab) {}
@@ -0,0 +1,93 @@
+//go:build mecabdebug
+
+package mecab
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// usageGuard detects concurrent use of MeCab.
+// Parse, ParseToString and ParseToNode are not safe for concurrent use,
+// and the nodes returned by ParseToNode are valid until the next parse.
+type usageGuard struct {
+	mu sync.Mutex
+
+	// op and stack are the name and the stack trace of the running call.
+	busy  bool
+	op    string
+	stack []byte
+
+	// gen is incremented on each parse call.
+	// lastStack is the stack trace of the latest parse call.
+	gen       uint64
+	lastStack []byte
+}
+
+func (g *usageGuard) enter(op string) {
+	stack := captureStack()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.busy {
+		panic(fmt.Sprintf(
+			"mecab: concurrent use of MeCab detected: %s is called while %s is running\n\n"+
+				"%s\n\n"+
+				"%s is called by:\n%s",
+			op, g.op, stack, g.op, g.stack,
+		))
+	}
+	g.busy = true
+	g.op = op
+	g.stack = stack
+	g.gen++
+	g.lastStack = stack
+}
+
+func (g *usageGuard) exit() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.busy = false
+	g.op = ""
+	g.stack = nil
+}
+
+func (g *usageGuard) nodeGuard() nodeGuard {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return nodeGuard{gen: g.gen}
+}
+
+// nodeGuard detects use of nodes that are invalidated by a new parse.
+type nodeGuard struct {
+	gen uint64
+}
+
+func (g nodeGuard) check(m *mecab) {
+	if m == nil || g.gen == 0 {
+		// the node is not owned by MeCab.
+		return
+	}
+	m.guard.mu.Lock()
+	defer m.guard.mu.Unlock()
+	if m.guard.gen != g.gen {
+		panic(fmt.Sprintf(
+			"mecab: the node is used after a new parse\n\n"+
+				"%s\n\n"+
+				"the new parse is called by:\n%s",
+			captureStack(), m.guard.lastStack,
+		))
+	}
+}
+
+func captureStack() []byte {
+	buf := make([]byte, 4096)
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
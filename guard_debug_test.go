@@ -0,0 +1,55 @@
+//go:build mecabdebug
+
+package mecab
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUsageGuard(t *testing.T) {
+	var g usageGuard
+	g.enter("Parse")
+	func() {
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("want panic, but not")
+			}
+			msg, ok := r.(string)
+			if !ok || !strings.Contains(msg, "ParseToNode is called while Parse is running") {
+				t.Errorf("unexpected panic: %v", r)
+			}
+		}()
+		g.enter("ParseToNode")
+	}()
+	g.exit()
+
+	// the guard is available after exit.
+	g.enter("ParseToNode")
+	g.exit()
+}
+
+func TestNodeGuard(t *testing.T) {
+	var m mecab
+	m.guard.enter("ParseToNode")
+	node := m.guard.nodeGuard()
+	m.guard.exit()
+
+	// the node is valid until the next parse.
+	node.check(&m)
+
+	m.guard.enter("Parse")
+	m.guard.exit()
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("want panic, but not")
+		}
+		msg, ok := r.(string)
+		if !ok || !strings.Contains(msg, "the node is used after a new parse") {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	node.check(&m)
+}
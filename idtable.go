@@ -0,0 +1,262 @@
+package mecab
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// IDTable maps the IDs of a dictionary to the feature patterns,
+// e.g. the part-of-speech IDs of pos-id.def and the context IDs of left-id.def and right-id.def.
+// A nil table has no entries.
+type IDTable struct {
+	ids      []int
+	patterns []string
+	byID     map[int]int
+	byName   map[string]int
+}
+
+// Len returns the number of the entries.
+func (t *IDTable) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.ids)
+}
+
+// Name returns the feature pattern of the ID, e.g. "名詞,固有名詞,人名,*".
+// It returns an empty string if the ID is not in the table.
+func (t *IDTable) Name(id int) string {
+	if t == nil {
+		return ""
+	}
+	i, ok := t.byID[id]
+	if !ok {
+		return ""
+	}
+	return t.patterns[i]
+}
+
+// ID returns the ID of the feature pattern. The pattern must be same as the one in the table.
+func (t *IDTable) ID(pattern string) (int, bool) {
+	if t == nil {
+		return 0, false
+	}
+	i, ok := t.byName[pattern]
+	if !ok {
+		return 0, false
+	}
+	return t.ids[i], true
+}
+
+// Match returns the ID of the first pattern that matches the feature, as mecab-dict-index does.
+// "*" in the pattern matches any field, "(a|b)" matches a or b,
+// and the fields of the feature after the pattern are ignored.
+func (t *IDTable) Match(feature string) (int, bool) {
+	if t == nil {
+		return 0, false
+	}
+	fields := SplitFeature(feature)
+	for i, pattern := range t.patterns {
+		if matchFeaturePattern(SplitFeature(pattern), fields) {
+			return t.ids[i], true
+		}
+	}
+	return 0, false
+}
+
+// All returns the IDs and the feature patterns in the order of the file.
+func (t *IDTable) All() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		if t == nil {
+			return
+		}
+		for i, id := range t.ids {
+			if !yield(id, t.patterns[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (t *IDTable) add(id int, pattern string) {
+	if _, ok := t.byID[id]; !ok {
+		t.byID[id] = len(t.ids)
+	}
+	if _, ok := t.byName[pattern]; !ok {
+		t.byName[pattern] = len(t.ids)
+	}
+	t.ids = append(t.ids, id)
+	t.patterns = append(t.patterns, pattern)
+}
+
+func matchFeaturePattern(pattern, fields []string) bool {
+	if len(pattern) > len(fields) {
+		return false
+	}
+	for i, p := range pattern {
+		if p == "*" {
+			continue
+		}
+		if strings.HasPrefix(p, "(") && strings.HasSuffix(p, ")") {
+			alternatives := strings.Split(p[1:len(p)-1], "|")
+			if !slices.Contains(alternatives, fields[i]) {
+				return false
+			}
+			continue
+		}
+		if p != fields[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// PosIDTable returns the part-of-speech IDs of pos-id.def in the dictionary directory.
+// The IDs are the values of [Node.PosID].
+// It returns an error that matches [ErrDictionaryNotFound] if the file doesn't exist.
+func (m Model) PosIDTable() (*IDTable, error) {
+	return m.idTable("PosIDTable", "pos-id.def")
+}
+
+// LeftIDTable returns the left context IDs of left-id.def in the dictionary directory.
+// The IDs are the values of [Node.LCAttr].
+// It returns an error that matches [ErrDictionaryNotFound] if the file doesn't exist.
+func (m Model) LeftIDTable() (*IDTable, error) {
+	return m.idTable("LeftIDTable", "left-id.def")
+}
+
+// RightIDTable returns the right context IDs of right-id.def in the dictionary directory.
+// The IDs are the values of [Node.RCAttr].
+// It returns an error that matches [ErrDictionaryNotFound] if the file doesn't exist.
+func (m Model) RightIDTable() (*IDTable, error) {
+	return m.idTable("RightIDTable", "right-id.def")
+}
+
+func (m Model) idTable(op, file string) (*IDTable, error) {
+	if m.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+	return m.m.files.idTable(op, m.DictionaryInfo(), file)
+}
+
+// PosName returns the feature pattern of the part-of-speech ID of the node in pos-id.def,
+// e.g. "名詞,固有名詞,人名,*".
+// It returns an empty string if pos-id.def of the dictionary is not available.
+//
+// The table is loaded when it is called for the first time by the nodes of the tagger,
+// and it is not updated by [Model.Swap].
+func (node Node) PosName() string {
+	id := node.PosID()
+	m := node.tagger()
+	if m == nil {
+		return ""
+	}
+	return m.names.posIDs(m).Name(id)
+}
+
+// idTable returns the table of the file in the directory of the system dictionary.
+// The tables are reloaded if the model is swapped.
+func (c *dictionaryFiles) idTable(op string, info []DictionaryInfo, file string) (*IDTable, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dicdir, ok := systemDicdir(info)
+	if !ok {
+		return nil, &Error{
+			Op:   op,
+			Kind: KindDictionaryNotFound,
+			err:  "no system dictionary",
+		}
+	}
+	if c.tableDir != dicdir {
+		c.tableDir, c.tables = dicdir, nil
+	}
+	if t, ok := c.tables[file]; ok {
+		return t, nil
+	}
+
+	t, err := readIDTable(op, filepath.Join(dicdir, file), file == "pos-id.def", info[0].Charset)
+	if err != nil {
+		return nil, err
+	}
+	if c.tables == nil {
+		c.tables = map[string]*IDTable{}
+	}
+	c.tables[file] = t
+	return t, nil
+}
+
+// readIDTable reads the ID definitions.
+// The lines of pos-id.def are "pattern id", and the lines of left-id.def and right-id.def are "id feature".
+//
+// The files are installed in the charset of the source of the dictionary,
+// which may differ from charset, the charset of the compiled dictionary.
+// e.g. ipadic compiled with --with-charset=utf8 keeps them in EUC-JP.
+// So they are decoded from charset or EUC-JP, only if they are not valid UTF-8.
+func readIDTable(op, name string, patternFirst bool, charset string) (*IDTable, error) {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, &Error{
+			Op:   op,
+			Path: name,
+			Kind: KindDictionaryNotFound,
+			err:  "no such file: " + name,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	text := string(data)
+	if !utf8.ValidString(text) {
+		if iconvCharset(charset) == "" {
+			charset = "EUC-JP"
+		}
+		c, err := newCodec(op, charset)
+		if err != nil {
+			return nil, err
+		}
+		text = c.decode(text)
+	}
+
+	t := &IDTable{
+		byID:   map[int]int{},
+		byName: map[string]int{},
+	}
+	s := bufio.NewScanner(strings.NewReader(text))
+	n := 0
+	for s.Scan() {
+		n++
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		var pattern, id string
+		if patternFirst {
+			if i := strings.LastIndexAny(line, " \t"); i >= 0 {
+				pattern, id = strings.TrimSpace(line[:i]), line[i+1:]
+			}
+		} else if i := strings.IndexAny(line, " \t"); i >= 0 {
+			id, pattern = line[:i], strings.TrimSpace(line[i+1:])
+		}
+		if pattern == "" {
+			return nil, defFileError(op, name, n, "want an ID and a feature pattern")
+		}
+		v, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, defFileError(op, name, n, "invalid ID: "+id)
+		}
+		t.add(v, pattern)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,122 @@
+package mecab
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadIDTable(t *testing.T) {
+	dir := t.TempDir()
+	pos := filepath.Join(dir, "pos-id.def")
+	if err := os.WriteFile(pos, []byte("その他,間投,*,* 0\n名詞,(固有名詞|代名詞),*,* 1\n名詞,*,*,* 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	table, err := readIDTable("Test", pos, true, "UTF-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Len() != 3 || table.Name(1) != "名詞,(固有名詞|代名詞),*,*" || table.Name(3) != "" {
+		t.Errorf("unexpected table: %v", table)
+	}
+	if id, ok := table.ID("名詞,*,*,*"); !ok || id != 2 {
+		t.Errorf("want 2, got %d, %v", id, ok)
+	}
+	tests := []struct {
+		feature string
+		id      int
+		ok      bool
+	}{
+		{"名詞,固有名詞,人名,姓,*,*,山田,ヤマダ,ヤマダ", 1, true},
+		{"名詞,代名詞,一般,*,*,*,私,ワタシ,ワタシ", 1, true},
+		{"名詞,一般,*,*,*,*,猫,ネコ,ネコ", 2, true},
+		{"動詞,自立,*,*,五段・カ行イ音便,基本形,行く,イク,イク", 0, false},
+		{"名詞,一般", 0, false},
+	}
+	for _, tt := range tests {
+		if id, ok := table.Match(tt.feature); id != tt.id || ok != tt.ok {
+			t.Errorf("%q: want %d, %v, got %d, %v", tt.feature, tt.id, tt.ok, id, ok)
+		}
+	}
+
+	left := filepath.Join(dir, "left-id.def")
+	if err := os.WriteFile(left, []byte("0 BOS/EOS,*,*,*,*,*,BOS/EOS\n1 その他,間投,*,*,*,*,*\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	table, err = readIDTable("Test", left, false, "UTF-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for id, name := range table.All() {
+		got = append(got, fmt.Sprintf("%d %s", id, name))
+	}
+	if len(got) != 2 || got[1] != "1 その他,間投,*,*,*,*,*" {
+		t.Errorf("unexpected entries: %q", got)
+	}
+
+	if _, err := readIDTable("Test", filepath.Join(dir, "right-id.def"), false, "UTF-8"); !errors.Is(err, ErrDictionaryNotFound) {
+		t.Errorf("want ErrDictionaryNotFound, got %v", err)
+	}
+
+	// a nil table has no entries.
+	table = nil
+	if table.Len() != 0 || table.Name(0) != "" {
+		t.Error("want empty table")
+	}
+}
+
+func TestModel_PosIDTable(t *testing.T) {
+	model, err := NewModel(rcfile(map[string]string{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer model.Destroy()
+	table, err := model.PosIDTable()
+	if err != nil {
+		t.Skipf("pos-id.def is not available: %v", err)
+	}
+
+	mecab, err := model.NewMeCab()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer mecab.Destroy()
+	node, err := mecab.ParseToNode("こんにちは世界")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for node := range node.Morphs() {
+		name := node.PosName()
+		if name == "" || name != table.Name(node.PosID()) {
+			t.Errorf("%s: unexpected pos name %q", node.Surface(), name)
+		}
+		if id, ok := table.Match(node.Feature()); !ok || id != node.PosID() {
+			t.Errorf("%s: want %d, got %d", node.Surface(), node.PosID(), id)
+		}
+	}
+}
+
+func TestReadIDTable_eucJP(t *testing.T) {
+	// ipadic compiled with --with-charset=utf8 keeps the definitions in EUC-JP.
+	name := filepath.Join(t.TempDir(), "pos-id.def")
+	data := "\xcc\xbe\xbb\xec,\xb8\xc7\xcd\xad\xcc\xbe\xbb\xec,*,* 1\n" // 名詞,固有名詞,*,* 1
+	if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	table, err := readIDTable("Test", name, true, "UTF-8")
+	if errors.Is(err, ErrCharsetMismatch) {
+		t.Skipf("EUC-JP is not supported: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := table.Name(1); name != "名詞,固有名詞,*,*" {
+		t.Errorf("want %q, got %q", "名詞,固有名詞,*,*", name)
+	}
+	if id, ok := table.Match("名詞,固有名詞,地域,一般,*,*,東京,トウキョウ,トーキョー"); !ok || id != 1 {
+		t.Errorf("want 1, got %d, %v", id, ok)
+	}
+}
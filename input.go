@@ -0,0 +1,103 @@
+package mecab
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// InputPolicy is the policy for inputs that contain NUL bytes or invalid UTF-8 sequences.
+type InputPolicy int
+
+const (
+	// InputPassThrough passes the input to MeCab as is. It is the default.
+	// Note that the result of [MeCab.Parse] is truncated at the NUL byte,
+	// because MeCab returns it as a NUL-terminated string.
+	InputPassThrough InputPolicy = iota
+
+	// InputReject rejects the input with [ErrInvalidInput].
+	InputReject
+
+	// InputReplace replaces each NUL byte and each byte of invalid UTF-8 sequences with U+FFFD.
+	// Note that it changes the byte offsets after the replaced bytes.
+	InputReplace
+)
+
+func (p InputPolicy) String() string {
+	switch p {
+	case InputPassThrough:
+		return "PassThrough"
+	case InputReject:
+		return "Reject"
+	case InputReplace:
+		return "Replace"
+	}
+	return ""
+}
+
+// SetInputPolicy sets the policy for inputs that contain NUL bytes or invalid UTF-8 sequences.
+// It is applied to Parse, ParseToString, ParseToNode and ParseLattice.
+// SetInputPolicy is not safe for concurrent use with parse calls.
+func (m MeCab) SetInputPolicy(p InputPolicy) {
+	if m.m.mecab == nil {
+		panic(errMeCabNotAvailable)
+	}
+	m.m.policy = p
+}
+
+// checkInput applies the policy to s.
+func checkInput(op, s string, p InputPolicy) (string, error) {
+	if p == InputPassThrough {
+		return s, nil
+	}
+
+	i := invalidInputOffset(s)
+	if i < 0 {
+		return s, nil
+	}
+
+	if p == InputReject {
+		msg := "invalid UTF-8 sequence at offset " + strconv.Itoa(i)
+		if s[i] == 0 {
+			msg = "NUL byte at offset " + strconv.Itoa(i)
+		}
+		return "", &Error{
+			Op:   op,
+			Kind: KindInvalidInput,
+			err:  msg,
+		}
+	}
+
+	buf := make([]byte, 0, len(s)+8)
+	buf = append(buf, s[:i]...)
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == 0 || (r == utf8.RuneError && size <= 1) {
+			buf = utf8.AppendRune(buf, utf8.RuneError)
+			i++
+			continue
+		}
+		buf = append(buf, s[i:i+size]...)
+		i += size
+	}
+	return string(buf), nil
+}
+
+// invalidInputOffset returns the offset of the first NUL byte or invalid UTF-8 sequence in s.
+// It returns -1 if s is valid.
+func invalidInputOffset(s string) int {
+	for i := 0; i < len(s); {
+		if s[i] == 0 {
+			return i
+		}
+		if s[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
@@ -0,0 +1,155 @@
+package mecab
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCheckInput(t *testing.T) {
+	tests := []struct {
+		input   string
+		policy  InputPolicy
+		want    string
+		wantErr bool
+	}{
+		{"こんにちは", InputReject, "こんにちは", false},
+		{"a\x00b", InputPassThrough, "a\x00b", false},
+		{"a\x00b", InputReject, "", true},
+		{"a\x00b", InputReplace, "a�b", false},
+		{"a\xffb", InputReject, "", true},
+		{"a\xffb", InputReplace, "a�b", false},
+		{"\xe3\x81", InputReplace, "��", false},
+	}
+
+	for _, tt := range tests {
+		got, err := checkInput("Parse", tt.input, tt.policy)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("%q, %s: want ErrInvalidInput, got %v", tt.input, tt.policy, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q, %s: unexpected error: %v", tt.input, tt.policy, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q, %s: want %q, got %q", tt.input, tt.policy, tt.want, got)
+		}
+	}
+}
+
+func TestLattice_Sentence_nul(t *testing.T) {
+	lattice, err := NewLattice()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer lattice.Destroy()
+
+	lattice.SetSentence("こんにちは\x00世界")
+	if got := lattice.Sentence(); got != "こんにちは\x00世界" {
+		t.Errorf("want %q, got %q", "こんにちは\x00世界", got)
+	}
+}
+
+func FuzzCheckInput(f *testing.F) {
+	f.Add("こんにちは世界")
+	f.Add("a\x00b")
+	f.Add("\xff\xfe")
+	f.Fuzz(func(t *testing.T, s string) {
+		got, err := checkInput("Parse", s, InputReplace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !utf8.ValidString(got) || invalidInputOffset(got) >= 0 {
+			t.Errorf("%q: the result %q is invalid", s, got)
+		}
+		if _, err := checkInput("Parse", got, InputReject); err != nil {
+			t.Errorf("%q: the result %q is rejected: %v", s, got, err)
+		}
+	})
+}
+
+func fuzzMeCab(f *testing.F) MeCab {
+	mecab, err := New(rcfile(map[string]string{}))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Cleanup(mecab.Destroy)
+
+	// XXX: avoid GC, MeCab 0.996 has GC problem (see https://github.com/taku910/mecab/pull/24)
+	mecab.Parse("")
+	return mecab
+}
+
+func addFuzzInputs(f *testing.F) {
+	f.Add([]byte("こんにちは世界"))
+	f.Add([]byte("こんにちは\x00世界"))
+	f.Add([]byte("\xe3\x81\x93\xff"))
+	f.Add([]byte{})
+}
+
+func FuzzParse(f *testing.F) {
+	mecab := fuzzMeCab(f)
+	addFuzzInputs(f)
+	f.Fuzz(func(t *testing.T, b []byte) {
+		for _, p := range []InputPolicy{InputPassThrough, InputReject, InputReplace} {
+			mecab.SetInputPolicy(p)
+			_, err := mecab.Parse(string(b))
+			if err != nil && !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("%q, %s: unexpected error: %v", b, p, err)
+			}
+		}
+	})
+}
+
+func FuzzParseToNode(f *testing.F) {
+	mecab := fuzzMeCab(f)
+	addFuzzInputs(f)
+	f.Fuzz(func(t *testing.T, b []byte) {
+		mecab.SetInputPolicy(InputReplace)
+		node, err := mecab.ParseToNode(string(b))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", b, err)
+			return
+		}
+		var length int
+		for n := range node.Nodes() {
+			length += n.RLength()
+			n.Surface()
+			n.Feature()
+		}
+		if want, _ := checkInput("ParseToNode", string(b), InputReplace); length > len(want) {
+			t.Errorf("%q: the total length %d is longer than the input %d", b, length, len(want))
+		}
+	})
+}
+
+func FuzzParseLattice(f *testing.F) {
+	mecab := fuzzMeCab(f)
+	addFuzzInputs(f)
+	f.Fuzz(func(t *testing.T, b []byte) {
+		lattice, err := NewLattice()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer lattice.Destroy()
+
+		lattice.SetSentence(string(b))
+		if got := lattice.Sentence(); got != string(b) {
+			t.Errorf("want %q, got %q", b, got)
+		}
+
+		mecab.SetInputPolicy(InputPassThrough)
+		if err := mecab.ParseLattice(lattice); err != nil {
+			t.Errorf("%q: unexpected error: %v", b, err)
+			return
+		}
+		for n := range lattice.Nodes() {
+			n.Surface()
+			n.Feature()
+		}
+	})
+}
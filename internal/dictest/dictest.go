@@ -0,0 +1,233 @@
+// Package dictest builds the compiled dictionaries of MeCab for tests.
+//
+// It writes the same formats as mecab-dict-index, but it is much simpler:
+// the costs and the context IDs are given by the tests, and it doesn't validate them.
+package dictest
+
+import (
+	"cmp"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+const (
+	dictionaryMagicID = 0xef718f77
+	dictionaryVersion = 102
+	unitSize          = 8
+	categoryNameSize  = 32
+	charMapSize       = 0xffff
+)
+
+// DoubleArray builds a double-array trie of Darts.
+// The values must not be negative.
+func DoubleArray(keys []string, values []int32) []byte {
+	type unit struct {
+		base  int32
+		check uint32
+		used  bool
+	}
+	units := []unit{{used: true}}
+	usedBase := map[int32]bool{}
+	grow := func(n int) {
+		for len(units) < n {
+			units = append(units, unit{})
+		}
+	}
+
+	var build func(idx []int, depth int) int32
+	build = func(idx []int, depth int) int32 {
+		// the codes of the children. 0 is the terminal.
+		var codes []int
+		for _, i := range idx {
+			code := 0
+			if depth < len(keys[i]) {
+				code = int(keys[i][depth]) + 1
+			}
+			if len(codes) == 0 || codes[len(codes)-1] != code {
+				codes = append(codes, code)
+			}
+		}
+
+		// find the base.
+		base := int32(1)
+	search:
+		for ; ; base++ {
+			if usedBase[base] {
+				continue
+			}
+			for _, code := range codes {
+				p := int(base) + code
+				grow(p + 1)
+				if units[p].used {
+					continue search
+				}
+			}
+			break
+		}
+		usedBase[base] = true
+		for _, code := range codes {
+			p := int(base) + code
+			units[p].used = true
+			units[p].check = uint32(base)
+		}
+
+		for _, code := range codes {
+			var children []int
+			for _, i := range idx {
+				c := 0
+				if depth < len(keys[i]) {
+					c = int(keys[i][depth]) + 1
+				}
+				if c == code {
+					children = append(children, i)
+				}
+			}
+			p := int(base) + code
+			if code == 0 {
+				units[p].base = -values[children[0]] - 1
+			} else {
+				units[p].base = build(children, depth+1)
+			}
+		}
+		return base
+	}
+
+	idx := make([]int, len(keys))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(a, b int) bool { return keys[idx[a]] < keys[idx[b]] })
+	units[0].base = build(idx, 0)
+
+	da := make([]byte, len(units)*unitSize)
+	for i, u := range units {
+		binary.LittleEndian.PutUint32(da[i*unitSize:], uint32(u.base))
+		binary.LittleEndian.PutUint32(da[i*unitSize+4:], u.check)
+	}
+	return da
+}
+
+// Entry is an entry of a dictionary.
+type Entry struct {
+	Surface string
+	LCAttr  uint16
+	RCAttr  uint16
+	PosID   uint16
+	WCost   int16
+	Feature string
+}
+
+// Dictionary builds a dictionary file.
+// typ is 0 for sys.dic, 1 for user dictionaries and 2 for unk.dic.
+// The entries of the same surface keep their order.
+func Dictionary(typ, lsize, rsize uint32, charset string, entries []Entry) []byte {
+	entries = slices.Clone(entries)
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		return cmp.Compare(a.Surface, b.Surface)
+	})
+
+	var keys []string
+	var values []int32
+	var tokens, features []byte
+	le := binary.LittleEndian
+	for i, e := range entries {
+		if len(keys) == 0 || keys[len(keys)-1] != e.Surface {
+			keys = append(keys, e.Surface)
+			values = append(values, int32(i)<<8)
+		}
+		values[len(values)-1]++
+
+		tokens = le.AppendUint16(tokens, e.LCAttr)
+		tokens = le.AppendUint16(tokens, e.RCAttr)
+		tokens = le.AppendUint16(tokens, e.PosID)
+		tokens = le.AppendUint16(tokens, uint16(e.WCost))
+		tokens = le.AppendUint32(tokens, uint32(len(features)))
+		tokens = le.AppendUint32(tokens, 0)
+		features = append(features, e.Feature...)
+		features = append(features, 0)
+	}
+	da := DoubleArray(keys, values)
+
+	size := 10*4 + 32 + len(da) + len(tokens) + len(features)
+	var buf []byte
+	for _, v := range []uint32{
+		uint32(size) ^ dictionaryMagicID, dictionaryVersion, typ, uint32(len(entries)),
+		lsize, rsize, uint32(len(da)), uint32(len(tokens)), uint32(len(features)), 0,
+	} {
+		buf = le.AppendUint32(buf, v)
+	}
+	var cs [32]byte
+	copy(cs[:], charset)
+	buf = append(buf, cs[:]...)
+	buf = append(buf, da...)
+	buf = append(buf, tokens...)
+	buf = append(buf, features...)
+	return buf
+}
+
+// Matrix builds a connection matrix file (matrix.bin).
+// cost returns the cost between the right context ID of the left node
+// and the left context ID of the right node.
+func Matrix(lsize, rsize int, cost func(rcAttr, lcAttr int) int16) []byte {
+	le := binary.LittleEndian
+	buf := le.AppendUint16(nil, uint16(lsize))
+	buf = le.AppendUint16(buf, uint16(rsize))
+	for lcAttr := range rsize {
+		for rcAttr := range lsize {
+			buf = le.AppendUint16(buf, uint16(cost(rcAttr, lcAttr)))
+		}
+	}
+	return buf
+}
+
+// Category is a character category of char.def.
+type Category struct {
+	Name   string
+	Invoke bool
+	Group  bool
+	Length int
+}
+
+// CharProperty builds a character definition file (char.bin).
+// category returns the indexes of the categories of the character.
+// The first one is the default category.
+func CharProperty(categories []Category, category func(r rune) []int) []byte {
+	le := binary.LittleEndian
+	buf := le.AppendUint32(nil, uint32(len(categories)))
+	for _, c := range categories {
+		var name [categoryNameSize]byte
+		copy(name[:], c.Name)
+		buf = append(buf, name[:]...)
+	}
+	for r := range rune(charMapSize) {
+		idx := category(r)
+		var v uint32
+		for _, i := range idx {
+			v |= 1 << uint(i)
+		}
+		def := categories[idx[0]]
+		v |= uint32(idx[0]) << 18
+		v |= uint32(def.Length) << 26
+		if def.Group {
+			v |= 1 << 30
+		}
+		if def.Invoke {
+			v |= 1 << 31
+		}
+		buf = le.AppendUint32(buf, v)
+	}
+	return buf
+}
+
+// WriteFiles writes the files into dir.
+func WriteFiles(dir string, files map[string][]byte) error {
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// Package viterbi is a pure Go implementation of the tokenizer of MeCab.
+//
+// It reads the dictionaries compiled by mecab-dict-index through package dic,
+// and reproduces the lattice, the Viterbi search and the N-best search of libmecab,
+// so that the output is the same as libmecab's.
+// Only UTF-8 dictionaries are supported, and the marginal probabilities and the partial parsing are not.
+package viterbi
+
+// Stat is the status of a node.
+type Stat uint8
+
+const (
+	// Normal is a node in the dictionary.
+	Normal Stat = 0
+
+	// Unknown is a node not in the dictionary.
+	Unknown Stat = 1
+
+	// BOS is the virtual node of the beginning of the sentence.
+	BOS Stat = 2
+
+	// EOS is the virtual node of the end of the sentence.
+	EOS Stat = 3
+
+	// EON is the virtual node of the end of the N-best results.
+	EON Stat = 4
+)
+
+// Request types, the same values as MECAB_ONE_BEST, MECAB_NBEST and so on.
+const (
+	OneBest = 1
+	NBest   = 2
+)
+
+// Node is a node of the lattice.
+type Node struct {
+	Prev  *Node
+	Next  *Node
+	ENext *Node
+	BNext *Node
+
+	// lpath is the list of the paths from the left nodes, used by the N-best search.
+	lpath *path
+
+	// Begin is the offset in bytes of the surface in the sentence.
+	Begin int
+
+	// Length is the length of the surface, and RLength also includes the white spaces before it.
+	Length  int
+	RLength int
+
+	ID       int
+	RCAttr   uint16
+	LCAttr   uint16
+	PosID    uint16
+	CharType uint8
+	Stat     Stat
+	IsBest   bool
+	WCost    int16
+	Cost     int64
+
+	Feature string
+
+	sentence string
+}
+
+// Surface returns the surface of the node.
+func (n *Node) Surface() string {
+	begin := min(n.Begin, len(n.sentence))
+	end := min(n.Begin+n.Length, len(n.sentence))
+	return n.sentence[begin:end]
+}
+
+// path is an edge of the lattice.
+type path struct {
+	lnode *Node
+	lnext *path
+	cost  int64
+}
+
+// Lattice is the lattice of a sentence.
+type Lattice struct {
+	sentence    string
+	hasSentence bool
+	requestType int
+
+	beginNodes []*Node
+	endNodes   []*Node
+	bos        *Node
+	eos        *Node
+	id         int
+
+	nbest  *nbestGenerator
+	writer *writer
+	what   string
+}
+
+// NewLattice returns a new lattice.
+func NewLattice() *Lattice {
+	return &Lattice{requestType: OneBest}
+}
+
+// SetSentence sets the sentence and clears the result.
+func (l *Lattice) SetSentence(s string) {
+	l.Clear()
+	l.sentence = s
+	l.hasSentence = true
+	l.beginNodes = make([]*Node, len(s)+4)
+	l.endNodes = make([]*Node, len(s)+4)
+}
+
+// Sentence returns the sentence.
+func (l *Lattice) Sentence() string {
+	return l.sentence
+}
+
+// Clear clears the sentence and the result.
+func (l *Lattice) Clear() {
+	l.sentence = ""
+	l.hasSentence = false
+	l.beginNodes = nil
+	l.endNodes = nil
+	l.bos = nil
+	l.eos = nil
+	l.id = 0
+	l.nbest = nil
+	l.what = ""
+}
+
+// IsAvailable reports whether the sentence is set.
+func (l *Lattice) IsAvailable() bool {
+	return l.hasSentence
+}
+
+// RequestType returns the request type.
+func (l *Lattice) RequestType() int {
+	return l.requestType
+}
+
+// SetRequestType sets the request type.
+func (l *Lattice) SetRequestType(t int) {
+	l.requestType = t
+}
+
+// BOSNode returns the BOS node, or nil if the sentence is not parsed.
+func (l *Lattice) BOSNode() *Node {
+	return l.bos
+}
+
+// EOSNode returns the EOS node, or nil if the sentence is not parsed.
+func (l *Lattice) EOSNode() *Node {
+	return l.eos
+}
+
+// BeginNodes returns the first node that begins at pos.
+func (l *Lattice) BeginNodes(pos int) *Node {
+	if pos < 0 || pos >= len(l.beginNodes) {
+		return nil
+	}
+	return l.beginNodes[pos]
+}
+
+// EndNodes returns the first node that ends at pos.
+func (l *Lattice) EndNodes(pos int) *Node {
+	if pos < 0 || pos >= len(l.endNodes) {
+		return nil
+	}
+	return l.endNodes[pos]
+}
+
+// What returns the last error.
+func (l *Lattice) What() string {
+	return l.what
+}
+
+// SetWhat sets the error.
+func (l *Lattice) SetWhat(what string) {
+	l.what = what
+}
+
+// Next moves to the next N-best result.
+// The first call returns the best result.
+func (l *Lattice) Next() bool {
+	if l.requestType&NBest == 0 {
+		l.what = "MECAB_NBEST request type is not set"
+		return false
+	}
+	if l.nbest == nil {
+		return false
+	}
+	return l.nbest.next()
+}
+
+// String returns the result in the output format of the tagger that parsed the lattice.
+func (l *Lattice) String() (string, error) {
+	w := l.writer
+	if w == nil {
+		w = defaultWriter
+	}
+	var buf []byte
+	buf, err := w.write(buf, l)
+	if err != nil {
+		l.what = err.Error()
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// newNode allocates a new node.
+func (l *Lattice) newNode() *Node {
+	n := &Node{
+		ID:       l.id,
+		sentence: l.sentence,
+	}
+	l.id++
+	return n
+}
@@ -0,0 +1,269 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shogo82148/go-mecab/dic"
+)
+
+// defaultMaxGroupingSize is the default of max-grouping-size.
+const defaultMaxGroupingSize = 24
+
+// maxNBest is the maximum of nbest.
+const maxNBest = 512
+
+// DictionaryInfo is the information of a dictionary.
+type DictionaryInfo struct {
+	Filename string
+	Charset  string
+	Size     uint
+	Type     int
+	LSize    uint
+	RSize    uint
+	Version  uint
+}
+
+// Model is a set of the dictionaries and the options.
+type Model struct {
+	dics      []*dic.Dictionary
+	filenames []string
+	unk       *dic.Dictionary
+	matrix    *dic.Matrix
+	char      *dic.CharProperty
+	unkTokens [][]dic.Token
+	space     dic.CharInfo
+
+	bosFeature      string
+	unkFeature      string
+	maxGroupingSize int
+	requestType     int
+	writer          *writer
+}
+
+// Open loads the resource files and the dictionaries.
+// The keys of args are the long options of MeCab without "--".
+func Open(args map[string]string) (*Model, error) {
+	p, err := newParam(args)
+	if err != nil {
+		return nil, err
+	}
+	dicdir, err := p.loadResource()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Model{}
+	if err := m.open(p, dicdir); err != nil {
+		m.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Model) open(p param, dicdir string) error {
+	var err error
+	name := filepath.Join(dicdir, "unk.dic")
+	if m.unk, err = openDictionary(name); err != nil {
+		return err
+	}
+	if m.char, err = dic.OpenCharProperty(filepath.Join(dicdir, "char.bin")); err != nil {
+		return fileError(filepath.Join(dicdir, "char.bin"), err)
+	}
+
+	name = filepath.Join(dicdir, "sys.dic")
+	sys, err := openDictionary(name)
+	if err != nil {
+		return err
+	}
+	m.dics = append(m.dics, sys)
+	m.filenames = append(m.filenames, name)
+	if sys.Type != dic.System {
+		return fmt.Errorf("not a system dictionary: %s", dicdir)
+	}
+	if !strings.EqualFold(normalizeCharset(sys.Charset), "UTF8") {
+		return fmt.Errorf("unsupported charset: %s: the pure Go implementation supports only UTF-8", sys.Charset)
+	}
+
+	if userdic := p["userdic"]; userdic != "" {
+		for _, name := range strings.Split(userdic, ",") {
+			d, err := openDictionary(name)
+			if err != nil {
+				return err
+			}
+			m.dics = append(m.dics, d)
+			m.filenames = append(m.filenames, name)
+			if d.Type != dic.User {
+				return fmt.Errorf("not a user dictionary: %s", name)
+			}
+			if d.Version != sys.Version || d.LSize != sys.LSize || d.RSize != sys.RSize ||
+				normalizeCharset(d.Charset) != normalizeCharset(sys.Charset) {
+				return fmt.Errorf("incompatible dictionary: %s", name)
+			}
+		}
+	}
+
+	for _, category := range m.char.Categories() {
+		tokens := m.unk.ExactMatch(category)
+		if tokens == nil {
+			return fmt.Errorf("cannot find UNK category: %s", category)
+		}
+		m.unkTokens = append(m.unkTokens, tokens)
+	}
+	m.space = m.char.Info(0x20)
+
+	m.bosFeature = p["bos-feature"]
+	if m.bosFeature == "" {
+		return errors.New("bos-feature is undefined in dicrc")
+	}
+	m.unkFeature = p["unk-feature"]
+	m.maxGroupingSize = defaultMaxGroupingSize
+	if v := p["max-grouping-size"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid max-grouping-size: %s", v)
+		}
+		if n > 0 {
+			m.maxGroupingSize = n
+		}
+	}
+
+	name = filepath.Join(dicdir, "matrix.bin")
+	if m.matrix, err = dic.OpenMatrix(name); err != nil {
+		return fileError(name, err)
+	}
+	if m.matrix.LSize() != int(sys.LSize) || m.matrix.RSize() != int(sys.RSize) {
+		return fmt.Errorf("context ID is out of range: %s", name)
+	}
+
+	m.requestType = OneBest
+	if v := p["nbest"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxNBest {
+			return fmt.Errorf("nbest = %s is out of range", v)
+		}
+		if n >= 2 {
+			m.requestType |= NBest
+		}
+	}
+	if v := p["lattice-level"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid lattice-level: %s", v)
+		}
+		if n >= 2 {
+			return errors.New("invalid argument: lattice-level 2 is not supported by the pure Go implementation")
+		}
+		if n >= 1 {
+			m.requestType |= NBest
+		}
+	}
+
+	m.writer, err = newWriter(p)
+	return err
+}
+
+func openDictionary(name string) (*dic.Dictionary, error) {
+	d, err := dic.Open(name)
+	if err != nil {
+		return nil, fileError(name, err)
+	}
+	return d, nil
+}
+
+func fileError(name string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("no such file or directory: %s", name)
+	}
+	return err
+}
+
+func normalizeCharset(charset string) string {
+	charset = strings.ToUpper(charset)
+	charset = strings.ReplaceAll(charset, "-", "")
+	return strings.ReplaceAll(charset, "_", "")
+}
+
+// Close closes the dictionaries.
+func (m *Model) Close() error {
+	var errs []error
+	for _, d := range m.dics {
+		errs = append(errs, d.Close())
+	}
+	if m.unk != nil {
+		errs = append(errs, m.unk.Close())
+	}
+	if m.matrix != nil {
+		errs = append(errs, m.matrix.Close())
+	}
+	if m.char != nil {
+		errs = append(errs, m.char.Close())
+	}
+	*m = Model{}
+	return errors.Join(errs...)
+}
+
+// RequestType returns the request type decided by the options.
+func (m *Model) RequestType() int {
+	return m.requestType
+}
+
+// DictionaryInfo returns the information of the system dictionary and the user dictionaries.
+func (m *Model) DictionaryInfo() []DictionaryInfo {
+	ret := make([]DictionaryInfo, len(m.dics))
+	for i, d := range m.dics {
+		ret[i] = DictionaryInfo{
+			Filename: m.filenames[i],
+			Charset:  d.Charset,
+			Size:     uint(d.LexSize),
+			Type:     int(d.Type),
+			LSize:    uint(d.LSize),
+			RSize:    uint(d.RSize),
+			Version:  uint(d.Version),
+		}
+	}
+	return ret
+}
+
+// NewLattice returns a new lattice that is written in the output format of the model.
+func (m *Model) NewLattice() *Lattice {
+	l := NewLattice()
+	l.requestType = m.requestType
+	l.writer = m.writer
+	return l
+}
+
+// Parse parses the sentence of the lattice.
+// It returns false if it fails, and the error is set to the lattice.
+func (m *Model) Parse(l *Lattice) bool {
+	if !l.hasSentence {
+		l.what = "sentence is not set"
+		return false
+	}
+	if l.requestType&^(OneBest|NBest|allocateSentence) != 0 {
+		l.what = "invalid argument: the request type is not supported by the pure Go implementation"
+		return false
+	}
+
+	// clear the previous result
+	clear(l.beginNodes)
+	clear(l.endNodes)
+	l.bos, l.eos, l.id, l.nbest, l.what = nil, nil, 0, nil, ""
+
+	nbest := l.requestType&NBest != 0
+	if !m.viterbi(l, nbest) {
+		return false
+	}
+	m.buildBestLattice(l)
+	if nbest {
+		l.nbest = newNBestGenerator(l)
+	}
+	return true
+}
+
+// allocateSentence is MECAB_ALLOCATE_SENTENCE. Go strings are always copied, so it is ignored.
+const allocateSentence = 64
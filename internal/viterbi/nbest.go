@@ -0,0 +1,100 @@
+package viterbi
+
+// queueElement is a partial path from EOS in the N-best search.
+type queueElement struct {
+	node *Node
+	next *queueElement
+	fx   int64
+	gx   int64
+}
+
+// nbestGenerator enumerates the paths in the order of the cost with the A* search.
+// The agenda is the binary heap of std::priority_queue of libstdc++,
+// so that the paths of the same cost are in the same order as libmecab.
+type nbestGenerator struct {
+	agenda []*queueElement
+}
+
+func newNBestGenerator(l *Lattice) *nbestGenerator {
+	g := &nbestGenerator{}
+	g.push(&queueElement{node: l.eos})
+	return g
+}
+
+// next links the nodes of the next best path by Prev and Next.
+func (g *nbestGenerator) next() bool {
+	for len(g.agenda) > 0 {
+		top := g.pop()
+		rnode := top.node
+		if rnode.Stat == BOS {
+			for n := top; n.next != nil; n = n.next {
+				n.node.Next = n.next.node
+				n.next.node.Prev = n.node
+			}
+			return true
+		}
+		for p := rnode.lpath; p != nil; p = p.lnext {
+			g.push(&queueElement{
+				node: p.lnode,
+				gx:   p.cost + top.gx,
+				fx:   p.lnode.Cost + p.cost + top.gx,
+				next: top,
+			})
+		}
+	}
+	return false
+}
+
+// less is the comparator of the priority queue. The top is the element with the smallest fx.
+func (g *nbestGenerator) less(a, b *queueElement) bool {
+	return a.fx > b.fx
+}
+
+func (g *nbestGenerator) push(e *queueElement) {
+	g.agenda = append(g.agenda, e)
+	g.pushHeap(len(g.agenda)-1, 0, e)
+}
+
+func (g *nbestGenerator) pop() *queueElement {
+	n := len(g.agenda)
+	top := g.agenda[0]
+	if n > 1 {
+		value := g.agenda[n-1]
+		g.agenda[n-1] = top
+		g.adjustHeap(0, n-1, value)
+	}
+	g.agenda[n-1] = nil
+	g.agenda = g.agenda[:n-1]
+	return top
+}
+
+// pushHeap is std::__push_heap.
+func (g *nbestGenerator) pushHeap(hole, top int, value *queueElement) {
+	parent := (hole - 1) / 2
+	for hole > top && g.less(g.agenda[parent], value) {
+		g.agenda[hole] = g.agenda[parent]
+		hole = parent
+		parent = (hole - 1) / 2
+	}
+	g.agenda[hole] = value
+}
+
+// adjustHeap is std::__adjust_heap.
+func (g *nbestGenerator) adjustHeap(hole, n int, value *queueElement) {
+	top := hole
+	child := hole
+	for child < (n-1)/2 {
+		child = 2 * (child + 1)
+		if g.less(g.agenda[child], g.agenda[child-1]) {
+			child--
+		}
+		g.agenda[hole] = g.agenda[child]
+		hole = child
+	}
+	if n&1 == 0 && child == (n-2)/2 {
+		child = 2 * (child + 1)
+		g.agenda[hole] = g.agenda[child-1]
+		hole = child - 1
+	}
+	g.pushHeap(hole, top, value)
+}
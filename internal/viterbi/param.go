@@ -0,0 +1,143 @@
+package viterbi
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// options are the long options of MeCab. The value is true if the option takes an argument.
+var options = map[string]bool{
+	"rcfile":             true,
+	"dicdir":             true,
+	"userdic":            true,
+	"lattice-level":      true,
+	"dictionary-info":    false,
+	"output-format-type": true,
+	"all-morphs":         false,
+	"nbest":              true,
+	"partial":            false,
+	"marginal":           false,
+	"max-grouping-size":  true,
+	"node-format":        true,
+	"unk-format":         true,
+	"bos-format":         true,
+	"eos-format":         true,
+	"eon-format":         true,
+	"unk-feature":        true,
+	"input-buffer-size":  true,
+	"allocate-sentence":  false,
+	"theta":              true,
+	"cost-factor":        true,
+	"output":             true,
+}
+
+// unsupported are the options that the pure Go implementation doesn't support.
+var unsupported = []string{"all-morphs", "partial", "marginal", "dictionary-info", "output"}
+
+// param is the configuration of MeCab.
+// The values that are set first win, as Param::load of MeCab doesn't overwrite them.
+type param map[string]string
+
+// newParam returns the parameters from the arguments.
+func newParam(args map[string]string) (param, error) {
+	p := param{}
+	for k, v := range args {
+		hasArg, ok := options[k]
+		if !ok {
+			return nil, fmt.Errorf("unrecognized option `--%s`", k)
+		}
+		if hasArg && v == "" {
+			return nil, fmt.Errorf("`--%s` requires an argument", k)
+		}
+		if !hasArg && v != "" {
+			return nil, fmt.Errorf("`--%s` doesn't allow an argument", k)
+		}
+		if !hasArg {
+			v = "1"
+		}
+		p[k] = v
+	}
+	for _, k := range unsupported {
+		if _, ok := p[k]; ok {
+			return nil, fmt.Errorf("invalid argument: `--%s` is not supported by the pure Go implementation", k)
+		}
+	}
+	return p, nil
+}
+
+// load reads the resource file. The values that are already set are not overwritten.
+func (p param) load(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no such file or directory: %s", name)
+		}
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("format error: %s", line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if _, ok := p[key]; !ok {
+			p[key] = value
+		}
+	}
+	return s.Err()
+}
+
+// DefaultRCFiles are the resource files that are searched
+// when neither the rcfile option nor MECABRC is given.
+var DefaultRCFiles = []string{"/usr/local/etc/mecabrc", "/etc/mecabrc", "/opt/homebrew/etc/mecabrc"}
+
+// rcfile returns the resource file, in the same order as MeCab.
+func (p param) rcfile() string {
+	if rc := p["rcfile"]; rc != "" {
+		return rc
+	}
+	if rc := os.Getenv("MECABRC"); rc != "" {
+		return rc
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		rc := filepath.Join(home, ".mecabrc")
+		if _, err := os.Stat(rc); err == nil {
+			return rc
+		}
+	}
+	for _, rc := range DefaultRCFiles {
+		if _, err := os.Stat(rc); err == nil {
+			return rc
+		}
+	}
+	return DefaultRCFiles[0]
+}
+
+// loadResource loads the resource file and the dicrc, and returns the dictionary directory.
+func (p param) loadResource() (string, error) {
+	rc := p.rcfile()
+	if err := p.load(rc); err != nil {
+		return "", err
+	}
+	dicdir := p["dicdir"]
+	if dicdir == "" {
+		dicdir = "."
+	}
+	dicdir = strings.ReplaceAll(dicdir, "$(rcpath)", filepath.Dir(rc))
+	if err := p.load(filepath.Join(dicdir, "dicrc")); err != nil {
+		return "", err
+	}
+	return dicdir, nil
+}
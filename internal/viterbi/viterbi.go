@@ -0,0 +1,249 @@
+package viterbi
+
+import (
+	"github.com/shogo82148/go-mecab/dic"
+)
+
+// maxLookupLength is the maximum length in bytes that a node can cover.
+const maxLookupLength = 65535
+
+// charInfo returns the information and the length in bytes of the character at p,
+// decoding UTF-8 in the same way as utf8_to_ucs2 of MeCab.
+// The characters out of the BMP and the invalid bytes are treated as U+0000.
+func (m *Model) charInfo(s string, p, end int) (dic.CharInfo, int) {
+	n := end - p
+	var b0 byte
+	if p < len(s) {
+		b0 = s[p]
+	}
+	var r rune
+	mblen := 1
+	switch {
+	case b0 < 0x80:
+		r = rune(b0)
+	case n >= 2 && b0&0xe0 == 0xc0:
+		mblen = 2
+		r = rune(b0&0x1f)<<6 | rune(s[p+1]&0x3f)
+	case n >= 3 && b0&0xf0 == 0xe0:
+		mblen = 3
+		r = rune(b0&0x0f)<<12 | rune(s[p+1]&0x3f)<<6 | rune(s[p+2]&0x3f)
+	case n >= 4 && b0&0xf8 == 0xf0:
+		mblen = 4
+	case n >= 5 && b0&0xfc == 0xf8:
+		mblen = 5
+	case n >= 6 && b0&0xfe == 0xfc:
+		mblen = 6
+	}
+	return m.char.Info(r), mblen
+}
+
+// seekToOtherType skips the characters of the same kind as c from p.
+// It returns the position of the first character of the other kind,
+// the information of the last character read, its length and the number of the skipped characters.
+func (m *Model) seekToOtherType(s string, p, end int, c dic.CharInfo) (int, dic.CharInfo, int, int) {
+	fail := c
+	mblen, clen := 0, 0
+	for p != end {
+		var info dic.CharInfo
+		info, mblen = m.charInfo(s, p, end)
+		fail = info
+		if c.Type&info.Type == 0 {
+			break
+		}
+		p += mblen
+		clen++
+		c = info
+	}
+	return p, fail, mblen, clen
+}
+
+// lookup returns the nodes that begin at begin, linked by BNext.
+func (m *Model) lookup(l *Lattice, begin int) *Node {
+	s := l.sentence
+	end := len(s)
+	if end-begin >= maxLookupLength {
+		end = begin + maxLookupLength
+	}
+
+	begin2, cinfo, mblen, _ := m.seekToOtherType(s, begin, end, m.space)
+
+	var result *Node
+	for _, d := range m.dics {
+		for _, match := range d.CommonPrefixSearch(s[begin2:end]) {
+			if match.Length == 0 {
+				continue
+			}
+			for _, token := range match.Tokens {
+				node := l.newNode()
+				m.readNodeInfo(d, token, node)
+				node.Begin = begin2
+				node.Length = match.Length
+				node.RLength = begin2 - begin + match.Length
+				node.Stat = Normal
+				node.CharType = uint8(cinfo.DefaultType)
+				node.BNext = result
+				result = node
+			}
+		}
+	}
+
+	if result != nil && !cinfo.Invoke {
+		return result
+	}
+
+	addUnknown := func(begin3 int) {
+		for _, token := range m.unkTokens[cinfo.DefaultType] {
+			node := l.newNode()
+			m.readNodeInfo(m.unk, token, node)
+			node.CharType = uint8(cinfo.DefaultType)
+			node.Begin = begin2
+			node.Length = begin3 - begin2
+			node.RLength = begin3 - begin
+			node.BNext = result
+			node.Stat = Unknown
+			if m.unkFeature != "" {
+				node.Feature = m.unkFeature
+			}
+			result = node
+		}
+	}
+
+	begin3 := begin2 + mblen
+	groupBegin3 := -1
+
+	if begin3 > end {
+		addUnknown(begin3)
+		return result
+	}
+
+	if cinfo.Group {
+		var clen int
+		groupBegin3, _, _, clen = m.seekToOtherType(s, begin3, end, cinfo)
+		if clen <= m.maxGroupingSize {
+			addUnknown(groupBegin3)
+		}
+	}
+
+	for i := 1; i <= cinfo.Length; i++ {
+		if begin3 > end {
+			break
+		}
+		if begin3 == groupBegin3 {
+			continue
+		}
+		addUnknown(begin3)
+		info, n := m.charInfo(s, begin3, end)
+		if cinfo.Type&info.Type == 0 {
+			break
+		}
+		begin3 += n
+	}
+
+	if result == nil {
+		addUnknown(begin3)
+	}
+	return result
+}
+
+func (m *Model) readNodeInfo(d *dic.Dictionary, token dic.Token, node *Node) {
+	node.LCAttr = token.LCAttr
+	node.RCAttr = token.RCAttr
+	node.PosID = token.PosID
+	node.WCost = token.WCost
+	node.Feature = d.Feature(token)
+}
+
+func (m *Model) newBOSNode(l *Lattice, stat Stat) *Node {
+	node := l.newNode()
+	node.Feature = m.bosFeature
+	node.IsBest = true
+	node.Stat = stat
+	return node
+}
+
+// viterbi builds the lattice and finds the best path.
+// If allPath is true, it also records the paths for the N-best search.
+func (m *Model) viterbi(l *Lattice, allPath bool) bool {
+	size := len(l.sentence)
+
+	bos := m.newBOSNode(l, BOS)
+	l.endNodes[0] = bos
+	l.bos = bos
+
+	for pos := 0; pos < size; pos++ {
+		if l.endNodes[pos] == nil {
+			continue
+		}
+		right := m.lookup(l, pos)
+		l.beginNodes[pos] = right
+		if !m.connect(l, pos, right, allPath) {
+			l.what = "too long sentence."
+			return false
+		}
+	}
+
+	eos := m.newBOSNode(l, EOS)
+	eos.Begin = size
+	l.beginNodes[size] = eos
+	l.eos = eos
+
+	for pos := size; pos >= 0; pos-- {
+		if l.endNodes[pos] != nil {
+			if !m.connect(l, pos, eos, allPath) {
+				l.what = "too long sentence."
+				return false
+			}
+			break
+		}
+	}
+	return true
+}
+
+// connect connects the nodes that end at pos to the right nodes.
+func (m *Model) connect(l *Lattice, pos int, right *Node, allPath bool) bool {
+	for ; right != nil; right = right.BNext {
+		var bestCost int64 = 2147483647
+		var best *Node
+		for left := l.endNodes[pos]; left != nil; left = left.ENext {
+			lcost := int64(m.matrix.Cost(int(left.RCAttr), int(right.LCAttr))) + int64(right.WCost)
+			cost := left.Cost + lcost
+			if cost < bestCost {
+				best = left
+				bestCost = cost
+			}
+			if allPath {
+				right.lpath = &path{
+					lnode: left,
+					lnext: right.lpath,
+					cost:  lcost,
+				}
+			}
+		}
+		if best == nil {
+			return false
+		}
+		right.Prev = best
+		right.Next = nil
+		right.Cost = bestCost
+		x := right.RLength + pos
+		for x >= len(l.endNodes) {
+			// an unknown word of trailing spaces may end after the sentence.
+			l.endNodes = append(l.endNodes, nil)
+			l.beginNodes = append(l.beginNodes, nil)
+		}
+		right.ENext = l.endNodes[x]
+		l.endNodes[x] = right
+	}
+	return true
+}
+
+// buildBestLattice links the nodes of the best path by Next.
+func (m *Model) buildBestLattice(l *Lattice) {
+	node := l.eos
+	for node.Prev != nil {
+		node.IsBest = true
+		prev := node.Prev
+		prev.Next = node
+		node = prev
+	}
+}
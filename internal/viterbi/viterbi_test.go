@@ -0,0 +1,284 @@
+package viterbi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shogo82148/go-mecab/internal/dictest"
+)
+
+// buildTestDictionary writes a small dictionary into a temporary directory.
+// The context ID 0 is BOS/EOS, and 1 is for the words.
+// The connection cost between words is 100.
+func buildTestDictionary(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	categories := []dictest.Category{
+		{Name: "DEFAULT", Group: true},
+		{Name: "SPACE", Group: true},
+		{Name: "KANJI", Length: 2},
+		{Name: "ALPHA", Invoke: true, Group: true},
+	}
+	category := func(r rune) []int {
+		switch {
+		case r == ' ':
+			return []int{1}
+		case 0x4e00 <= r && r <= 0x9fff:
+			return []int{2}
+		case 'a' <= r && r <= 'z':
+			return []int{3}
+		}
+		return []int{0}
+	}
+	sys := []dictest.Entry{
+		{Surface: "東京", LCAttr: 1, RCAttr: 1, PosID: 1, WCost: 2900, Feature: "名詞,固有名詞,トウキョウ"},
+		{Surface: "東京都", LCAttr: 1, RCAttr: 1, PosID: 1, WCost: 5100, Feature: "名詞,固有名詞,トウキョウト"},
+		{Surface: "都", LCAttr: 1, RCAttr: 1, PosID: 2, WCost: 2000, Feature: "名詞,接尾,ト"},
+		{Surface: "京都", LCAttr: 1, RCAttr: 1, PosID: 1, WCost: 2500, Feature: "名詞,固有名詞,キョウト"},
+		{Surface: "東", LCAttr: 1, RCAttr: 1, PosID: 3, WCost: 4000, Feature: "名詞,一般,ヒガシ"},
+	}
+	unk := []dictest.Entry{
+		{Surface: "DEFAULT", LCAttr: 1, RCAttr: 1, WCost: 9000, Feature: "記号,一般,*"},
+		{Surface: "SPACE", LCAttr: 1, RCAttr: 1, WCost: 9000, Feature: "記号,空白,*"},
+		{Surface: "KANJI", LCAttr: 1, RCAttr: 1, WCost: 8000, Feature: "名詞,一般,*"},
+		{Surface: "ALPHA", LCAttr: 1, RCAttr: 1, WCost: 1000, Feature: "名詞,固有名詞,*"},
+	}
+	matrix := func(rcAttr, lcAttr int) int16 {
+		if rcAttr == 1 && lcAttr == 1 {
+			return 100
+		}
+		return 0
+	}
+	err := dictest.WriteFiles(dir, map[string][]byte{
+		"sys.dic":    dictest.Dictionary(0, 2, 2, "UTF-8", sys),
+		"unk.dic":    dictest.Dictionary(2, 2, 2, "UTF-8", unk),
+		"matrix.bin": dictest.Matrix(2, 2, matrix),
+		"char.bin":   dictest.CharProperty(categories, category),
+		"dicrc": []byte("bos-feature = BOS/EOS,*,*\n" +
+			"node-format-cost = %m\\t%pc\\t%pC\\n\n" +
+			"eos-format-cost = EOS\\t%pc\\n\n" +
+			"node-format-yomi = %pS%f[2]\n" +
+			"unk-format-yomi = %M\n" +
+			"eos-format-yomi = \\n\n"),
+		"mecabrc": []byte("; the resource file for the test\n"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func openTestModel(t *testing.T, args map[string]string) *Model {
+	t.Helper()
+	dir := buildTestDictionary(t)
+	a := map[string]string{
+		"rcfile": filepath.Join(dir, "mecabrc"),
+		"dicdir": dir,
+	}
+	for k, v := range args {
+		a[k] = v
+	}
+	m, err := Open(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { m.Close() })
+	return m
+}
+
+func parse(t *testing.T, m *Model, s string) string {
+	t.Helper()
+	l := m.NewLattice()
+	l.SetSentence(s)
+	if !m.Parse(l) {
+		t.Fatal(l.What())
+	}
+	ret, err := l.String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+func TestParse(t *testing.T) {
+	m := openTestModel(t, nil)
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   "東京都",
+			want: "東京\t名詞,固有名詞,トウキョウ\n都\t名詞,接尾,ト\nEOS\n",
+		},
+		{
+			// unknown words are grouped, and white spaces are skipped.
+			in:   "abc 東京",
+			want: "abc\t名詞,固有名詞,*\n東京\t名詞,固有名詞,トウキョウ\nEOS\n",
+		},
+		{
+			// the unknown word of the trailing space is not connected to EOS.
+			in:   "東京 ",
+			want: "東京\t名詞,固有名詞,トウキョウ\nEOS\n",
+		},
+		{
+			// the length of unknown kanji words is up to 2.
+			in:   "漢字語",
+			want: "漢字\t名詞,一般,*\n語\t名詞,一般,*\nEOS\n",
+		},
+		{
+			in:   "",
+			want: "EOS\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := parse(t, m, tt.in); got != tt.want {
+			t.Errorf("%q: want %q, got %q", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestParse_nodes(t *testing.T) {
+	m := openTestModel(t, nil)
+	l := m.NewLattice()
+	l.SetSentence("abc 東京")
+	if !m.Parse(l) {
+		t.Fatal(l.What())
+	}
+
+	bos := l.BOSNode()
+	if bos.ID != 0 || bos.Stat != BOS || !bos.IsBest || bos.Feature != "BOS/EOS,*,*" {
+		t.Errorf("unexpected BOS: %#v", bos)
+	}
+	node := bos.Next
+	if node.Surface() != "abc" || node.Stat != Unknown || node.CharType != 3 || node.Cost != 1000 {
+		t.Errorf("unexpected node: %#v", node)
+	}
+	node = node.Next
+	if node.Surface() != "東京" || node.Length != 6 || node.RLength != 7 || node.Cost != 1000+100+2900 {
+		t.Errorf("unexpected node: %#v", node)
+	}
+	if node.Next != l.EOSNode() || node.Next.Next != nil {
+		t.Errorf("unexpected EOS: %#v", node.Next)
+	}
+
+	// 東 and 東京 begin at 4, after the white space.
+	var got []string
+	for n := l.BeginNodes(3); n != nil; n = n.BNext {
+		got = append(got, n.Surface())
+	}
+	if strings.Join(got, " ") != "東京 東" {
+		t.Errorf("unexpected begin nodes: %v", got)
+	}
+}
+
+func TestParse_format(t *testing.T) {
+	m := openTestModel(t, map[string]string{"output-format-type": "cost"})
+	want := "東京\t2900\t0\n都\t5000\t100\nEOS\t5000\n"
+	if got := parse(t, m, "東京都"); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	m = openTestModel(t, map[string]string{"output-format-type": "yomi"})
+	want = "トウキョウ xyz\n"
+	if got := parse(t, m, "東京 xyz"); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	m = openTestModel(t, map[string]string{"output-format-type": "wakati"})
+	want = "abc 東京 \n"
+	if got := parse(t, m, "abc 東京"); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	m = openTestModel(t, map[string]string{"node-format": "%F/[0,1,2]|%f[1]\\n", "eos-format": "\\n"})
+	want = "名詞/固有名詞/トウキョウ|固有名詞\n名詞/接尾/ト|接尾\n\n"
+	if got := parse(t, m, "東京都"); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestParse_nbest(t *testing.T) {
+	m := openTestModel(t, map[string]string{"nbest": "2"})
+	l := m.NewLattice()
+	l.SetSentence("東京都")
+	if !m.Parse(l) {
+		t.Fatal(l.What())
+	}
+
+	var got []string
+	for l.Next() {
+		s, err := l.String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, s)
+	}
+	want := []string{
+		"東京\t名詞,固有名詞,トウキョウ\n都\t名詞,接尾,ト\nEOS\n",
+		"東京都\t名詞,固有名詞,トウキョウト\nEOS\n",
+		"東\t名詞,一般,ヒガシ\n京都\t名詞,固有名詞,キョウト\nEOS\n",
+	}
+	if strings.Join(got, "") != strings.Join(want, "") {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestOpen_userdic(t *testing.T) {
+	dir := buildTestDictionary(t)
+	userdic := filepath.Join(dir, "user.dic")
+	user := []dictest.Entry{
+		{Surface: "東京都", LCAttr: 1, RCAttr: 1, PosID: 1, WCost: 1000, Feature: "名詞,固有名詞,トーキョート"},
+	}
+	if err := os.WriteFile(userdic, dictest.Dictionary(1, 2, 2, "UTF-8", user), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := openTestModel(t, map[string]string{"userdic": userdic})
+	want := "東京都\t名詞,固有名詞,トーキョート\nEOS\n"
+	if got := parse(t, m, "東京都"); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	info := m.DictionaryInfo()
+	if len(info) != 2 || info[0].Type != 0 || info[1].Type != 1 || info[1].Filename != userdic || info[1].Size != 1 {
+		t.Errorf("unexpected dictionary info: %#v", info)
+	}
+}
+
+func TestOpen_error(t *testing.T) {
+	dir := buildTestDictionary(t)
+	rc := filepath.Join(dir, "mecabrc")
+	tests := []struct {
+		args map[string]string
+		want string
+	}{
+		{
+			args: map[string]string{"rcfile": rc, "dicdir": dir, "unknown": "1"},
+			want: "unrecognized option `--unknown`",
+		},
+		{
+			args: map[string]string{"rcfile": rc, "dicdir": dir, "output-format-type": "unknown"},
+			want: "unknown format type [unknown]",
+		},
+		{
+			args: map[string]string{"rcfile": rc, "dicdir": filepath.Join(dir, "missing")},
+			want: "no such file or directory: " + filepath.Join(dir, "missing", "dicrc"),
+		},
+		{
+			args: map[string]string{"rcfile": filepath.Join(dir, "missingrc")},
+			want: "no such file or directory: " + filepath.Join(dir, "missingrc"),
+		},
+		{
+			args: map[string]string{"rcfile": rc, "dicdir": dir, "marginal": ""},
+			want: "invalid argument: `--marginal` is not supported by the pure Go implementation",
+		},
+	}
+	for _, tt := range tests {
+		_, err := Open(tt.args)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%v: want %q, got %v", tt.args, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package viterbi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+type writerStyle int
+
+const (
+	styleLattice writerStyle = iota
+	styleWakati
+	styleNone
+	styleUser
+)
+
+// writer writes the result in the output format, as Writer of MeCab.
+type writer struct {
+	style writerStyle
+	node  string
+	unk   string
+	bos   string
+	eos   string
+}
+
+// defaultWriter writes "surface\tfeature" lines and "EOS".
+var defaultWriter = &writer{}
+
+func newWriter(p param) (*writer, error) {
+	ostyle := p["output-format-type"]
+	switch ostyle {
+	case "wakati":
+		return &writer{style: styleWakati}, nil
+	case "none":
+		return &writer{style: styleNone}, nil
+	case "dump", "em":
+		return nil, fmt.Errorf("invalid argument: output format type %s is not supported by the pure Go implementation", ostyle)
+	}
+
+	suffix := ""
+	if ostyle != "" {
+		suffix = "-" + ostyle
+		if p["node-format"+suffix] == "" {
+			return nil, fmt.Errorf("unknown format type [%s]", ostyle)
+		}
+	}
+	node := p["node-format"+suffix]
+	unk := p["unk-format"+suffix]
+	bos := p["bos-format"+suffix]
+	eos := p["eos-format"+suffix]
+	if node == "" && unk == "" && bos == "" && eos == "" {
+		return &writer{style: styleLattice}, nil
+	}
+
+	w := &writer{
+		style: styleUser,
+		node:  `%m\t%H\n`,
+		eos:   `EOS\n`,
+	}
+	if node != "" {
+		w.node = node
+	}
+	if bos != "" {
+		w.bos = bos
+	}
+	if eos != "" {
+		w.eos = eos
+	}
+	w.unk = w.node
+	if unk != "" {
+		w.unk = unk
+	}
+	return w, nil
+}
+
+// write writes the best path, or the current path of the N-best search.
+func (w *writer) write(buf []byte, l *Lattice) ([]byte, error) {
+	if l.bos == nil {
+		return nil, errors.New("the lattice is not parsed")
+	}
+	switch w.style {
+	case styleWakati:
+		for node := l.bos.Next; node.Next != nil; node = node.Next {
+			buf = append(buf, node.Surface()...)
+			buf = append(buf, ' ')
+		}
+		return append(buf, '\n'), nil
+	case styleNone:
+		return buf, nil
+	case styleUser:
+		var err error
+		if buf, err = writeNode(buf, w.bos, l, l.bos); err != nil {
+			return nil, err
+		}
+		node := l.bos.Next
+		for ; node.Next != nil; node = node.Next {
+			format := w.node
+			if node.Stat == Unknown {
+				format = w.unk
+			}
+			if buf, err = writeNode(buf, format, l, node); err != nil {
+				return nil, err
+			}
+		}
+		return writeNode(buf, w.eos, l, node)
+	}
+
+	for node := l.bos.Next; node.Next != nil; node = node.Next {
+		buf = append(buf, node.Surface()...)
+		buf = append(buf, '\t')
+		buf = append(buf, node.Feature...)
+		buf = append(buf, '\n')
+	}
+	return append(buf, "EOS\n"...), nil
+}
+
+// writeNode writes the node in the format, as Writer::writeNode of MeCab.
+func writeNode(buf []byte, format string, l *Lattice, node *Node) ([]byte, error) {
+	var fields []string
+	for p := 0; p < len(format); p++ {
+		switch format[p] {
+		default:
+			buf = append(buf, format[p])
+		case '\\':
+			p++
+			buf = append(buf, escapedChar(format, p))
+		case '%':
+			p++
+			if p >= len(format) {
+				return nil, errors.New("unknown meta char: ")
+			}
+			switch format[p] {
+			default:
+				return nil, fmt.Errorf("unknown meta char: %c", format[p])
+			case 'S':
+				buf = append(buf, l.sentence...)
+			case 'L':
+				buf = strconv.AppendInt(buf, int64(len(l.sentence)), 10)
+			case 'm':
+				buf = append(buf, node.Surface()...)
+			case 'M':
+				buf = append(buf, spaceBefore(l, node)...)
+				buf = append(buf, node.Surface()...)
+			case 'h':
+				buf = strconv.AppendUint(buf, uint64(node.PosID), 10)
+			case '%':
+				buf = append(buf, '%')
+			case 'c':
+				buf = strconv.AppendInt(buf, int64(node.WCost), 10)
+			case 'H':
+				buf = append(buf, node.Feature...)
+			case 't':
+				buf = strconv.AppendUint(buf, uint64(node.CharType), 10)
+			case 's':
+				buf = strconv.AppendUint(buf, uint64(node.Stat), 10)
+			case 'P':
+				buf = append(buf, '0')
+			case 'p':
+				p++
+				if p >= len(format) {
+					return nil, errors.New("[iseSCwcnblLh] is required after %p")
+				}
+				switch format[p] {
+				default:
+					return nil, errors.New("[iseSCwcnblLh] is required after %p")
+				case 'i':
+					buf = strconv.AppendInt(buf, int64(node.ID), 10)
+				case 'S':
+					buf = append(buf, spaceBefore(l, node)...)
+				case 's':
+					buf = strconv.AppendInt(buf, int64(node.Begin), 10)
+				case 'e':
+					buf = strconv.AppendInt(buf, int64(node.Begin+node.Length), 10)
+				case 'C':
+					buf = strconv.AppendInt(buf, node.Cost-prevCost(node)-int64(node.WCost), 10)
+				case 'w':
+					buf = strconv.AppendInt(buf, int64(node.WCost), 10)
+				case 'c':
+					buf = strconv.AppendInt(buf, node.Cost, 10)
+				case 'n':
+					buf = strconv.AppendInt(buf, node.Cost-prevCost(node), 10)
+				case 'b':
+					if node.IsBest {
+						buf = append(buf, '*')
+					} else {
+						buf = append(buf, ' ')
+					}
+				case 'P', 'A', 'B':
+					buf = append(buf, '0')
+				case 'l':
+					buf = strconv.AppendInt(buf, int64(node.Length), 10)
+				case 'L':
+					buf = strconv.AppendInt(buf, int64(node.RLength), 10)
+				case 'h':
+					p++
+					if p >= len(format) {
+						return nil, errors.New("lr is required after %ph")
+					}
+					switch format[p] {
+					default:
+						return nil, errors.New("lr is required after %ph")
+					case 'l':
+						buf = strconv.AppendUint(buf, uint64(node.LCAttr), 10)
+					case 'r':
+						buf = strconv.AppendUint(buf, uint64(node.RCAttr), 10)
+					}
+				}
+			case 'F', 'f':
+				if node.Feature == "" {
+					return nil, errors.New("no feature information available")
+				}
+				if fields == nil {
+					fields = splitCSV(node.Feature)
+				}
+				separator := byte('\t')
+				if format[p] == 'F' {
+					p++
+					if p < len(format) && format[p] == '\\' {
+						p++
+						separator = escapedChar(format, p)
+					} else if p < len(format) {
+						separator = format[p]
+					}
+				}
+				p++
+				if p >= len(format) || format[p] != '[' {
+					return nil, errors.New("cannot find '['")
+				}
+				n := 0
+				sep := false
+				for p++; ; p++ {
+					if p >= len(format) {
+						return nil, errors.New("cannot find ']'")
+					}
+					c := format[p]
+					if '0' <= c && c <= '9' {
+						n = 10*n + int(c-'0')
+						continue
+					}
+					if c != ',' && c != ']' {
+						return nil, errors.New("cannot find ']'")
+					}
+					if n >= len(fields) {
+						return nil, errors.New("given index is out of range")
+					}
+					isfil := fields[n] == "" || fields[n][0] != '*'
+					if isfil {
+						if sep {
+							buf = append(buf, separator)
+						}
+						buf = append(buf, fields[n]...)
+					}
+					if c == ']' {
+						break
+					}
+					sep = isfil
+					n = 0
+				}
+			}
+		}
+	}
+	return buf, nil
+}
+
+func spaceBefore(l *Lattice, node *Node) string {
+	begin := node.Begin - (node.RLength - node.Length)
+	return l.sentence[max(begin, 0):min(node.Begin, len(l.sentence))]
+}
+
+func prevCost(node *Node) int64 {
+	if node.Prev == nil {
+		return 0
+	}
+	return node.Prev.Cost
+}
+
+func escapedChar(format string, p int) byte {
+	if p >= len(format) {
+		return 0
+	}
+	switch format[p] {
+	case '0':
+		return 0
+	case 'a':
+		return '\a'
+	case 'b':
+		return '\b'
+	case 't':
+		return '\t'
+	case 'n':
+		return '\n'
+	case 'v':
+		return '\v'
+	case 'f':
+		return '\f'
+	case 'r':
+		return '\r'
+	case 's':
+		return ' '
+	case '\\':
+		return '\\'
+	}
+	return 0
+}
+
+// splitCSV splits the feature as tokenizeCSV of MeCab.
+// A field quoted by '"' may contain commas, and "" in it is a '"'.
+func splitCSV(s string) []string {
+	var fields []string
+	for i := 0; ; {
+		var field []byte
+		if i < len(s) && s[i] == '"' {
+			i++
+			for i < len(s) {
+				if s[i] == '"' {
+					if i+1 < len(s) && s[i+1] == '"' {
+						field = append(field, '"')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				field = append(field, s[i])
+				i++
+			}
+			for i < len(s) && s[i] != ',' {
+				i++
+			}
+		} else {
+			for i < len(s) && s[i] != ',' {
+				field = append(field, s[i])
+				i++
+			}
+		}
+		fields = append(fields, string(field))
+		if i >= len(s) {
+			return fields
+		}
+		i++ // skip ','
+	}
+}
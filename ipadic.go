@@ -0,0 +1,57 @@
+package mecab
+
+// IPADICFeature is a feature of IPADIC.
+// The placeholder "*" is decoded as an empty string.
+type IPADICFeature struct {
+	// POS1 is the part-of-speech, e.g. "名詞".
+	POS1 string
+
+	// POS2, POS3 and POS4 are the sub categories of the part-of-speech.
+	POS2 string
+	POS3 string
+	POS4 string
+
+	// ConjugationType is the type of the conjugation, e.g. "五段・カ行イ音便".
+	ConjugationType string
+
+	// ConjugationForm is the form of the conjugation, e.g. "連用タ接続".
+	ConjugationForm string
+
+	// BaseForm is the base form.
+	BaseForm string
+
+	// Reading is the reading in katakana.
+	// It is empty for unknown words.
+	Reading string
+
+	// Pronunciation is the pronunciation in katakana.
+	// It is empty for unknown words.
+	Pronunciation string
+}
+
+// ParseIPADICFeature parses the feature string of IPADIC.
+// Missing fields, e.g. the reading of unknown words, are decoded as empty strings.
+func ParseIPADICFeature(feature string) IPADICFeature {
+	fields := SplitFeature(feature)
+	return IPADICFeature{
+		POS1:            featureField(fields, 0),
+		POS2:            featureField(fields, 1),
+		POS3:            featureField(fields, 2),
+		POS4:            featureField(fields, 3),
+		ConjugationType: featureField(fields, 4),
+		ConjugationForm: featureField(fields, 5),
+		BaseForm:        featureField(fields, 6),
+		Reading:         featureField(fields, 7),
+		Pronunciation:   featureField(fields, 8),
+	}
+}
+
+// IPADIC returns the feature of the node decoded as IPADIC.
+func (node Node) IPADIC() IPADICFeature {
+	return ParseIPADICFeature(node.Feature())
+}
+
+// IPADIC returns the feature of the token decoded as IPADIC.
+func (t Token) IPADIC() IPADICFeature {
+	return ParseIPADICFeature(t.Feature)
+}
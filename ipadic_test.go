@@ -0,0 +1,62 @@
+package mecab
+
+import "testing"
+
+func TestParseIPADICFeature(t *testing.T) {
+	tests := []struct {
+		feature string
+		want    IPADICFeature
+	}{
+		{
+			feature: "名詞,一般,*,*,*,*,世界,セカイ,セカイ",
+			want: IPADICFeature{
+				POS1:          "名詞",
+				POS2:          "一般",
+				BaseForm:      "世界",
+				Reading:       "セカイ",
+				Pronunciation: "セカイ",
+			},
+		},
+		{
+			feature: "動詞,自立,*,*,五段・カ行イ音便,連用タ接続,書く,カイ,カイ",
+			want: IPADICFeature{
+				POS1:            "動詞",
+				POS2:            "自立",
+				ConjugationType: "五段・カ行イ音便",
+				ConjugationForm: "連用タ接続",
+				BaseForm:        "書く",
+				Reading:         "カイ",
+				Pronunciation:   "カイ",
+			},
+		},
+		{
+			// unknown words don't have the reading and the pronunciation.
+			feature: "名詞,固有名詞,組織,*,*,*,*",
+			want: IPADICFeature{
+				POS1: "名詞",
+				POS2: "固有名詞",
+				POS3: "組織",
+			},
+		},
+		{
+			feature: "BOS/EOS,*,*,*,*,*,*,*,*",
+			want: IPADICFeature{
+				POS1: "BOS/EOS",
+			},
+		},
+		{
+			feature: "",
+			want:    IPADICFeature{},
+		},
+	}
+
+	for _, tt := range tests {
+		got := ParseIPADICFeature(tt.feature)
+		if got != tt.want {
+			t.Errorf("%q: want %#v, got %#v", tt.feature, tt.want, got)
+		}
+		if got := (Token{Feature: tt.feature}).IPADIC(); got != tt.want {
+			t.Errorf("%q: want %#v, got %#v", tt.feature, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package mecab
+
+import (
+	"iter"
+	"unicode/utf8"
+)
+
+// Nodes returns an iterator over the node and the following nodes,
+// including BOS and EOS nodes.
+// The nodes are valid until the owner (MeCab or Lattice) parses a new sentence or is destroyed.
+func (node Node) Nodes() iter.Seq[Node] {
+	return func(yield func(Node) bool) {
+		for ; !node.IsZero(); node = node.Next() {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+// Morphs returns an iterator over the node and the following nodes, excluding BOS and EOS nodes.
+// The nodes are valid until the owner (MeCab or Lattice) parses a new sentence or is destroyed.
+func (node Node) Morphs() iter.Seq[Node] {
+	return func(yield func(Node) bool) {
+		for ; !node.IsZero(); node = node.Next() {
+			if isBoundary(node) {
+				continue
+			}
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+// IndexedNodes is same as [Node.Nodes], but it also yields the index of the node.
+func (node Node) IndexedNodes() iter.Seq2[int, Node] {
+	return indexed(node.Nodes())
+}
+
+// IndexedMorphs is same as [Node.Morphs], but it also yields the index of the node.
+// The index of the first morph is 0.
+func (node Node) IndexedMorphs() iter.Seq2[int, Node] {
+	return indexed(node.Morphs())
+}
+
+// Nodes returns an iterator over the nodes of the best result,
+// including BOS and EOS nodes.
+// The nodes are valid until the lattice is modified or destroyed.
+func (l Lattice) Nodes() iter.Seq[Node] {
+	return l.BOSNode().Nodes()
+}
+
+// Morphs returns an iterator over the nodes of the best result, excluding BOS and EOS nodes.
+// The nodes are valid until the lattice is modified or destroyed.
+func (l Lattice) Morphs() iter.Seq[Node] {
+	return l.BOSNode().Morphs()
+}
+
+// IndexedNodes is same as [Lattice.Nodes], but it also yields the index of the node.
+func (l Lattice) IndexedNodes() iter.Seq2[int, Node] {
+	return l.BOSNode().IndexedNodes()
+}
+
+// IndexedMorphs is same as [Lattice.Morphs], but it also yields the index of the node.
+func (l Lattice) IndexedMorphs() iter.Seq2[int, Node] {
+	return l.BOSNode().IndexedMorphs()
+}
+
+// BeginNodes returns an iterator over the nodes that begin at pos.
+// pos is the byte offset in the sentence in UTF-8, even if the charset of the dictionary is not UTF-8.
+// It yields nothing if pos is out of the sentence or is not at a boundary of characters.
+func (l Lattice) BeginNodes(pos int) iter.Seq[Node] {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	return func(yield func(Node) bool) {
+		pos, ok := l.latticePos(pos)
+		if !ok {
+			return
+		}
+		node := l.beginNodes(pos)
+		for ; !node.IsZero(); node = node.BNext() {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+// EndNodes returns an iterator over the nodes that end at pos.
+// pos is the byte offset in the sentence in UTF-8, even if the charset of the dictionary is not UTF-8.
+// It yields nothing if pos is out of the sentence or is not at a boundary of characters.
+func (l Lattice) EndNodes(pos int) iter.Seq[Node] {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	return func(yield func(Node) bool) {
+		pos, ok := l.latticePos(pos)
+		if !ok {
+			return
+		}
+		node := l.endNodes(pos)
+		for ; !node.IsZero(); node = node.ENext() {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+// latticePos converts pos, the offset in the sentence in UTF-8,
+// into the offset in the sentence of the lattice, which is in the charset of the dictionary.
+func (l Lattice) latticePos(pos int) (int, bool) {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	// the nodes are available after parsing.
+	if pos < 0 || pos > l.Size() || !l.IsAvailable() {
+		return 0, false
+	}
+	if l.l.codec == nil {
+		return pos, true
+	}
+	s := l.Sentence()
+	if pos < len(s) && !utf8.RuneStart(s[pos]) {
+		return 0, false
+	}
+	prefix, err := l.l.codec.encode("BeginNodes", s[:pos])
+	if err != nil {
+		return 0, false
+	}
+	return len(prefix), true
+}
+
+func isBoundary(node Node) bool {
+	stat := node.Stat()
+	return stat == BOSNode || stat == EOSNode
+}
+
+func indexed(seq iter.Seq[Node]) iter.Seq2[int, Node] {
+	return func(yield func(int, Node) bool) {
+		i := 0
+		for node := range seq {
+			if !yield(i, node) {
+				return
+			}
+			i++
+		}
+	}
+}
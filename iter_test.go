@@ -0,0 +1,196 @@
+package mecab
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNode_Morphs(t *testing.T) {
+	mecab, err := New(rcfile(map[string]string{}))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer mecab.Destroy()
+
+	// XXX: avoid GC, MeCab 0.996 has GC problem (see https://github.com/taku910/mecab/pull/24)
+	mecab.Parse("")
+
+	node, err := mecab.ParseToNode("こんにちは世界")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	var nodes []string
+	for n := range node.Nodes() {
+		nodes = append(nodes, n.Stat().String())
+	}
+	if want := []string{"BOS", "Normal", "Normal", "EOS"}; !slices.Equal(nodes, want) {
+		t.Errorf("want %v, got %v", want, nodes)
+	}
+
+	var morphs []string
+	for n := range node.Morphs() {
+		morphs = append(morphs, n.Surface())
+	}
+	if want := []string{"こんにちは", "世界"}; !slices.Equal(morphs, want) {
+		t.Errorf("want %v, got %v", want, morphs)
+	}
+
+	for i, n := range node.IndexedMorphs() {
+		if n.Surface() != morphs[i] {
+			t.Errorf("%d: want %s, got %s", i, morphs[i], n.Surface())
+		}
+	}
+}
+
+func TestNode_Morphs_break(t *testing.T) {
+	mecab, err := New(rcfile(map[string]string{}))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer mecab.Destroy()
+
+	// XXX: avoid GC, MeCab 0.996 has GC problem (see https://github.com/taku910/mecab/pull/24)
+	mecab.Parse("")
+
+	node, err := mecab.ParseToNode("こんにちは世界")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	var count int
+	for i, n := range node.IndexedMorphs() {
+		count++
+		if i != 0 || n.Surface() != "こんにちは" {
+			t.Errorf("unexpected node: %d, %s", i, n.Surface())
+		}
+		break
+	}
+	if count != 1 {
+		t.Errorf("want 1, got %d", count)
+	}
+
+	// the iterator can be used again.
+	var surfaces []string
+	for n := range node.Morphs() {
+		surfaces = append(surfaces, n.Surface())
+	}
+	if want := []string{"こんにちは", "世界"}; !slices.Equal(surfaces, want) {
+		t.Errorf("want %v, got %v", want, surfaces)
+	}
+}
+
+func TestLattice_Morphs(t *testing.T) {
+	mecab, err := New(rcfile(map[string]string{}))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer mecab.Destroy()
+
+	lattice, err := NewLattice()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer lattice.Destroy()
+
+	// the lattice is not parsed yet.
+	for range lattice.BeginNodes(0) {
+		t.Error("want no nodes, but got")
+	}
+
+	lattice.SetSentence("こんにちは世界")
+	if err := mecab.ParseLattice(lattice); err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	var morphs []Node
+	for n := range lattice.Morphs() {
+		morphs = append(morphs, n)
+	}
+
+	// the nodes are valid after the iteration, until the lattice is modified.
+	if len(morphs) != 2 || morphs[0].Surface() != "こんにちは" || morphs[1].Surface() != "世界" {
+		t.Errorf("unexpected morphs: %v", morphs)
+	}
+
+	pos := len("こんにちは")
+	var found bool
+	for n := range lattice.BeginNodes(pos) {
+		if n.Surface() == "世界" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("世界 is not found in the begin nodes")
+	}
+
+	found = false
+	for n := range lattice.EndNodes(pos) {
+		if n.Surface() == "こんにちは" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("こんにちは is not found in the end nodes")
+	}
+
+	for range lattice.BeginNodes(lattice.Size() + 1) {
+		t.Error("want no nodes, but got")
+	}
+	for range lattice.EndNodes(-1) {
+		t.Error("want no nodes, but got")
+	}
+}
+
+func TestLattice_BeginNodes_eucJP(t *testing.T) {
+	if _, err := newCodec("test", "EUC-JP"); err != nil {
+		t.Skipf("euc-jp is not supported: %v", err)
+	}
+	mecab, err := New(buildPureGoDictionary(t, "EUC-JP"))
+	if err != nil {
+		t.Skipf("the dictionary in EUC-JP is not supported: %v", err)
+	}
+	defer mecab.Destroy()
+
+	lattice, err := NewLattice()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer lattice.Destroy()
+
+	lattice.SetSentence("東京都")
+	if err := mecab.ParseLattice(lattice); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the offsets are in UTF-8, not in EUC-JP.
+	if got, want := lattice.Size(), len("東京都"); got != want {
+		t.Errorf("want size %d, got %d", want, got)
+	}
+	pos := len("東京")
+	var begins, ends []string
+	for n := range lattice.BeginNodes(pos) {
+		begins = append(begins, n.Surface())
+	}
+	for n := range lattice.EndNodes(pos) {
+		ends = append(ends, n.Surface())
+	}
+	if !slices.Contains(begins, "都") {
+		t.Errorf("都 is not found in the begin nodes: %q", begins)
+	}
+	if !slices.Contains(ends, "東京") {
+		t.Errorf("東京 is not found in the end nodes: %q", ends)
+	}
+
+	// pos is not at a boundary of characters.
+	for range lattice.BeginNodes(1) {
+		t.Error("want no nodes, but got")
+	}
+}
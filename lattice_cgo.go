@@ -0,0 +1,227 @@
+//go:build cgo && !purego
+
+package mecab
+
+// #include <mecab.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"runtime"
+	"unsafe"
+)
+
+type lattice struct {
+	lattice *C.mecab_lattice_t
+
+	// codec is not nil if the sentence in the lattice is converted
+	// into the charset of the dictionary.
+	codec *codec
+	// tagger is the last tagger that parsed the lattice.
+	tagger *mecab
+
+	// metrics is the hook that recorded the creation, or nil.
+	// The destruction is reported to the same hook.
+	metrics Metrics
+}
+
+func newLattice(l *C.mecab_lattice_t) *lattice {
+	ret := &lattice{
+		lattice: l,
+	}
+	runtime.SetFinalizer(ret, finalizeLattice)
+	ret.metrics = recordCreated(ObjectLattice)
+	return ret
+}
+
+func finalizeLattice(l *lattice) {
+	if l.lattice != nil {
+		C.mecab_lattice_destroy(l.lattice)
+		recordDestroyed(ObjectLattice, l.metrics)
+	}
+	l.lattice = nil
+}
+
+// NewLattice creates new lattice.
+func NewLattice() (Lattice, error) {
+	// C.mecab_lattice_new sets an error in the thread local storage.
+	// so C.mecab_lattice_new and C.mecab_strerror must be call in same thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	l := C.mecab_lattice_new()
+	if l == nil {
+		return Lattice{}, newError("NewLattice", nil, nil)
+	}
+	return Lattice{l: newLattice(l)}, nil
+}
+
+// Destroy frees the lattice.
+func (l Lattice) Destroy() {
+	runtime.SetFinalizer(l.l, nil) // clear the finalizer
+	if l.l.lattice != nil {
+		C.mecab_lattice_destroy(l.l.lattice)
+		recordDestroyed(ObjectLattice, l.l.metrics)
+	}
+	l.l.lattice = nil
+}
+
+// Clear set empty string to the lattice.
+func (l Lattice) Clear() {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	C.mecab_lattice_clear(l.l.lattice)
+	l.l.codec = nil
+	runtime.KeepAlive(l.l)
+}
+
+// IsAvailable returns the lattice is available.
+func (l Lattice) IsAvailable() bool {
+	if l.l.lattice == nil {
+		return false
+	}
+	available := C.mecab_lattice_is_available(l.l.lattice) != 0
+	runtime.KeepAlive(l.l)
+	return available
+}
+
+// BOSNode returns the Begin Of Sentence node.
+func (l Lattice) BOSNode() Node {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	return Node{
+		node:    C.mecab_lattice_get_bos_node(l.l.lattice),
+		lattice: l.l,
+	}
+}
+
+// EOSNode returns the End Of Sentence node.
+func (l Lattice) EOSNode() Node {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	return Node{
+		node:    C.mecab_lattice_get_eos_node(l.l.lattice),
+		lattice: l.l,
+	}
+}
+
+// Sentence returns the sentence in the lattice.
+func (l Lattice) Sentence() string {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	sentence := C.mecab_lattice_get_sentence(l.l.lattice)
+	if sentence == nil {
+		return ""
+	}
+	// use the size to preserve NUL bytes in the sentence.
+	s := C.GoStringN(sentence, C.int(C.mecab_lattice_get_size(l.l.lattice)))
+	runtime.KeepAlive(l.l)
+	if l.l.codec != nil {
+		return l.l.codec.decode(s)
+	}
+	return s
+}
+
+// SetSentence set the sentence in the lattice.
+// If the charset of the dictionary is not UTF-8,
+// the sentence is converted into the charset by [MeCab.ParseLattice].
+func (l Lattice) SetSentence(s string) {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	l.setSentence(s)
+	l.l.codec = nil
+}
+
+func (l Lattice) setSentence(s string) {
+	length := C.size_t(len(s))
+	input := C.CString(s)
+	defer C.free(unsafe.Pointer(input))
+
+	C.mecab_lattice_add_request_type(l.l.lattice, C.int(RequestTypeAllocateSentence)) // MECAB_ALLOCATE_SENTENCE = 64
+	C.mecab_lattice_set_sentence2(l.l.lattice, input, length)
+	runtime.KeepAlive(l.l)
+}
+
+func (l Lattice) String() string {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	s := C.GoString(C.mecab_lattice_tostr(l.l.lattice))
+	runtime.KeepAlive(l.l)
+	if l.l.codec != nil {
+		return l.l.codec.decode(s)
+	}
+	return s
+}
+
+// Next obtains next-best result. The internal linked list structure is updated.
+// You should set [RequestTypeNBest] in advance.
+// Return false if no more results are available or [RequestType] is invalid.
+func (l Lattice) Next() bool {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	next := C.mecab_lattice_next(l.l.lattice) != 0
+	runtime.KeepAlive(l)
+	return next
+}
+
+// RequestType returns the request type.
+func (l Lattice) RequestType() RequestType {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	return RequestType(C.mecab_lattice_get_request_type(l.l.lattice))
+}
+
+// SetRequestType sets the request type.
+func (l Lattice) SetRequestType(t RequestType) {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	C.mecab_lattice_add_request_type(l.l.lattice, C.int(t))
+	runtime.KeepAlive(l)
+}
+
+// AddRequestType adds the request type.
+func (l Lattice) AddRequestType(t RequestType) {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	C.mecab_lattice_add_request_type(l.l.lattice, C.int(t))
+	runtime.KeepAlive(l)
+}
+
+// Size returns the length of the sentence in bytes.
+// It is the length in UTF-8, even if the sentence is converted into the charset of the dictionary.
+func (l Lattice) Size() int {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	if l.l.codec != nil {
+		return len(l.Sentence())
+	}
+	size := int(C.mecab_lattice_get_size(l.l.lattice))
+	runtime.KeepAlive(l.l)
+	return size
+}
+
+// beginNodes returns the first node that begins at pos.
+func (l Lattice) beginNodes(pos int) Node {
+	return Node{
+		node:    C.mecab_lattice_get_begin_nodes(l.l.lattice, C.size_t(pos)),
+		lattice: l.l,
+	}
+}
+
+// endNodes returns the first node that ends at pos.
+func (l Lattice) endNodes(pos int) Node {
+	return Node{
+		node:    C.mecab_lattice_get_end_nodes(l.l.lattice, C.size_t(pos)),
+		lattice: l.l,
+	}
+}
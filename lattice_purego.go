@@ -0,0 +1,177 @@
+//go:build !cgo || purego
+
+package mecab
+
+import (
+	"runtime"
+
+	"github.com/shogo82148/go-mecab/internal/viterbi"
+)
+
+type lattice struct {
+	lattice *viterbi.Lattice
+
+	// codec is always nil, because the pure Go implementation supports only UTF-8.
+	codec *codec
+	// tagger is the last tagger that parsed the lattice.
+	tagger *mecab
+
+	// metrics is the hook that recorded the creation, or nil.
+	// The destruction is reported to the same hook.
+	metrics Metrics
+}
+
+func newLattice(l *viterbi.Lattice) *lattice {
+	ret := &lattice{
+		lattice: l,
+	}
+	runtime.SetFinalizer(ret, finalizeLattice)
+	ret.metrics = recordCreated(ObjectLattice)
+	return ret
+}
+
+func finalizeLattice(l *lattice) {
+	if l.lattice != nil {
+		recordDestroyed(ObjectLattice, l.metrics)
+	}
+	l.lattice = nil
+}
+
+// NewLattice creates new lattice.
+func NewLattice() (Lattice, error) {
+	return Lattice{l: newLattice(viterbi.NewLattice())}, nil
+}
+
+// Destroy frees the lattice.
+func (l Lattice) Destroy() {
+	runtime.SetFinalizer(l.l, nil) // clear the finalizer
+	if l.l.lattice != nil {
+		recordDestroyed(ObjectLattice, l.l.metrics)
+	}
+	l.l.lattice = nil
+}
+
+// Clear set empty string to the lattice.
+func (l Lattice) Clear() {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	l.l.lattice.Clear()
+}
+
+// IsAvailable returns the lattice is available.
+func (l Lattice) IsAvailable() bool {
+	if l.l.lattice == nil {
+		return false
+	}
+	return l.l.lattice.IsAvailable()
+}
+
+// BOSNode returns the Begin Of Sentence node.
+func (l Lattice) BOSNode() Node {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	return Node{
+		node:    l.l.lattice.BOSNode(),
+		lattice: l.l,
+	}
+}
+
+// EOSNode returns the End Of Sentence node.
+func (l Lattice) EOSNode() Node {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	return Node{
+		node:    l.l.lattice.EOSNode(),
+		lattice: l.l,
+	}
+}
+
+// Sentence returns the sentence in the lattice.
+func (l Lattice) Sentence() string {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	return l.l.lattice.Sentence()
+}
+
+// SetSentence set the sentence in the lattice.
+func (l Lattice) SetSentence(s string) {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	l.setSentence(s)
+}
+
+func (l Lattice) setSentence(s string) {
+	l.AddRequestType(RequestTypeAllocateSentence)
+	l.l.lattice.SetSentence(s)
+}
+
+func (l Lattice) String() string {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	s, err := l.l.lattice.String()
+	if err != nil {
+		return ""
+	}
+	return truncateAtNUL(s)
+}
+
+// Next obtains next-best result. The internal linked list structure is updated.
+// You should set [RequestTypeNBest] in advance.
+// Return false if no more results are available or [RequestType] is invalid.
+func (l Lattice) Next() bool {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	return l.l.lattice.Next()
+}
+
+// RequestType returns the request type.
+func (l Lattice) RequestType() RequestType {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	return RequestType(l.l.lattice.RequestType())
+}
+
+// SetRequestType sets the request type.
+func (l Lattice) SetRequestType(t RequestType) {
+	l.AddRequestType(t)
+}
+
+// AddRequestType adds the request type.
+func (l Lattice) AddRequestType(t RequestType) {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	l.l.lattice.SetRequestType(l.l.lattice.RequestType() | int(t))
+}
+
+// Size returns the length of the sentence in bytes.
+func (l Lattice) Size() int {
+	if l.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	return len(l.l.lattice.Sentence())
+}
+
+// beginNodes returns the first node that begins at pos.
+func (l Lattice) beginNodes(pos int) Node {
+	return Node{
+		node:    l.l.lattice.BeginNodes(pos),
+		lattice: l.l,
+	}
+}
+
+// endNodes returns the first node that ends at pos.
+func (l Lattice) endNodes(pos int) Node {
+	return Node{
+		node:    l.l.lattice.EndNodes(pos),
+		lattice: l.l,
+	}
+}
@@ -0,0 +1,231 @@
+//go:build cgo && !purego
+
+package mecab
+
+// #include <mecab.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// to introduce garbage-collection while maintaining backwards compatibility.
+type mecab struct {
+	mecab  *C.mecab_t
+	guard  usageGuard
+	policy InputPolicy
+
+	// codec converts strings if the charset of the dictionary is not UTF-8.
+	codec *codec
+
+	// names is the names of the IDs for Node.CharTypeName and Node.PosName.
+	names nodeNames
+
+	// metrics is the hook that recorded the creation, or nil.
+	// The destruction is reported to the same hook.
+	metrics Metrics
+}
+
+func newMeCab(m *C.mecab_t) *mecab {
+	ret := &mecab{
+		mecab: m,
+	}
+	runtime.SetFinalizer(ret, finalizeMeCab)
+	ret.metrics = recordCreated(ObjectMeCab)
+	return ret
+}
+
+func finalizeMeCab(m *mecab) {
+	if m.mecab != nil {
+		C.mecab_destroy(m.mecab)
+		recordDestroyed(ObjectMeCab, m.metrics)
+	}
+	m.mecab = nil
+}
+
+// New returns new MeCab parser.
+func New(args map[string]string) (MeCab, error) {
+	// build the argument
+	opts := make([]*C.char, 0, len(args)+2)
+	opt := C.CString("mecab")
+	defer C.free(unsafe.Pointer(opt))
+	opts = append(opts, opt)
+	opt = C.CString("--allocate-sentence")
+	defer C.free(unsafe.Pointer(opt))
+	opts = append(opts, opt)
+	for k, v := range args {
+		var goopt string
+		if v != "" {
+			goopt = fmt.Sprintf("--%s=%s", k, v)
+		} else {
+			goopt = "--" + k
+		}
+		opt := C.CString(goopt)
+		defer C.free(unsafe.Pointer(opt))
+		opts = append(opts, opt)
+	}
+
+	// C.mecab_new sets an error in the thread local storage.
+	// so C.mecab_new and C.mecab_strerror must be call in same thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// create new MeCab
+	m := C.mecab_new(C.int(len(opts)), (**C.char)(&opts[0]))
+	if m == nil {
+		return MeCab{}, newError("New", nil, args)
+	}
+
+	ret := MeCab{
+		m: newMeCab(m),
+	}
+	if err := ret.m.initCodec("New"); err != nil {
+		ret.Destroy()
+		return MeCab{}, err
+	}
+	return ret, nil
+}
+
+// Destroy frees the MeCab parser.
+func (m MeCab) Destroy() {
+	runtime.SetFinalizer(m.m, nil) // clear the finalizer
+	if m.m.mecab != nil {
+		C.mecab_destroy(m.m.mecab)
+		recordDestroyed(ObjectMeCab, m.m.metrics)
+	}
+	m.m.mecab = nil
+}
+
+// Parse parses the string and returns the result as string.
+// Parse is not safe for concurrent use by multiple goroutines.
+func (m MeCab) Parse(s string) (string, error) {
+	if m.m.mecab == nil {
+		panic(errMeCabNotAvailable)
+	}
+	s, err := checkInput("Parse", s, m.m.policy)
+	if err != nil {
+		return "", err
+	}
+	if m.m.codec != nil {
+		s, err = m.m.codec.encode("Parse", s)
+		if err != nil {
+			return "", err
+		}
+	}
+	length := C.size_t(len(s))
+	input := C.CString(s)
+	defer C.free(unsafe.Pointer(input))
+
+	m.m.guard.enter("Parse")
+	defer m.m.guard.exit()
+
+	obs := observeParse("Parse", len(s))
+	result := C.mecab_sparse_tostr2(m.m.mecab, input, length)
+	if result == nil {
+		err := newError("Parse", m.m.mecab, nil)
+		obs.done(Node{}, err)
+		return "", err
+	}
+	obs.done(Node{}, nil)
+	runtime.KeepAlive(s)
+	runtime.KeepAlive(m.m)
+	if m.m.codec != nil {
+		return m.m.codec.decode(C.GoString(result)), nil
+	}
+	return C.GoString(result), nil
+}
+
+// ParseLattice parses the lattice and returns the result as string.
+// ParseLattice is safe for concurrent use by multiple goroutines.
+// Create a lattice for each goroutine.
+func (m MeCab) ParseLattice(lattice Lattice) error {
+	if m.m.mecab == nil {
+		panic(errMeCabNotAvailable)
+	}
+	if lattice.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	if lattice.l.codec == nil && (m.m.policy != InputPassThrough || m.m.codec != nil) {
+		// the sentence is set by SetSentence, and it is not checked yet.
+		s := lattice.Sentence()
+		checked, err := checkInput("ParseLattice", s, m.m.policy)
+		if err != nil {
+			return err
+		}
+		if m.m.codec != nil {
+			encoded, err := m.m.codec.encode("ParseLattice", checked)
+			if err != nil {
+				return err
+			}
+			lattice.setSentence(encoded)
+			lattice.l.codec = m.m.codec
+		} else if checked != s {
+			lattice.setSentence(checked)
+		}
+	}
+
+	var obs parseObserver
+	if getMetrics() != nil {
+		obs = observeParse("ParseLattice", int(C.mecab_lattice_get_size(lattice.l.lattice)))
+	}
+	if C.mecab_parse_lattice(m.m.mecab, lattice.l.lattice) == 0 {
+		err := newLatticeError("ParseLattice", lattice.l.lattice, m.m.mecab)
+		obs.done(Node{}, err)
+		return err
+	}
+	lattice.l.tagger = m.m
+	obs.done(lattice.BOSNode(), nil)
+	runtime.KeepAlive(m.m)
+	runtime.KeepAlive(lattice.l)
+	return nil
+}
+
+// ParseToNode parses the string and returns the result as [Node].
+// ParseToNode is not safe for concurrent use by multiple goroutines.
+func (m MeCab) ParseToNode(s string) (Node, error) {
+	if m.m.mecab == nil {
+		panic(errMeCabNotAvailable)
+	}
+	s, err := checkInput("ParseToNode", s, m.m.policy)
+	if err != nil {
+		return Node{}, err
+	}
+	if m.m.codec != nil {
+		s, err = m.m.codec.encode("ParseToNode", s)
+		if err != nil {
+			return Node{}, err
+		}
+	}
+	length := C.size_t(len(s))
+	input := C.CString(s)
+	defer C.free(unsafe.Pointer(input))
+
+	m.m.guard.enter("ParseToNode")
+	defer m.m.guard.exit()
+
+	obs := observeParse("ParseToNode", len(s))
+	node := C.mecab_sparse_tonode2(m.m.mecab, input, length)
+	if node == nil {
+		err := newError("ParseToNode", m.m.mecab, nil)
+		obs.done(Node{}, err)
+		return Node{}, err
+	}
+	obs.done(Node{node: node}, nil)
+	runtime.KeepAlive(s)
+	return Node{
+		node:  node,
+		mecab: m.m,
+		guard: m.m.guard.nodeGuard(),
+	}, nil
+}
+
+// Error returns the error of MeCab.
+func (m MeCab) Error() error {
+	if m.m.mecab == nil {
+		panic(errMeCabNotAvailable)
+	}
+	return newError("", m.m.mecab, nil)
+}
@@ -0,0 +1,216 @@
+//go:build !cgo || purego
+
+package mecab
+
+import (
+	"runtime"
+	"strings"
+
+	"github.com/shogo82148/go-mecab/internal/viterbi"
+)
+
+// to introduce garbage-collection while maintaining backwards compatibility.
+type mecab struct {
+	mecab  *tagger
+	guard  usageGuard
+	policy InputPolicy
+
+	// codec is always nil, because the pure Go implementation supports only UTF-8.
+	codec *codec
+
+	// names is the names of the IDs for Node.CharTypeName and Node.PosName.
+	names nodeNames
+
+	// metrics is the hook that recorded the creation, or nil.
+	// The destruction is reported to the same hook.
+	metrics Metrics
+}
+
+// tagger is the pure Go implementation of mecab_t.
+type tagger struct {
+	model *model
+
+	// own is true if the model is created by New, and it is destroyed with the tagger.
+	own bool
+
+	// lattice is used by Parse and ParseToNode.
+	lattice *viterbi.Lattice
+
+	// err is the last error.
+	err string
+}
+
+func newMeCab(t *tagger) *mecab {
+	ret := &mecab{
+		mecab: t,
+	}
+	runtime.SetFinalizer(ret, finalizeMeCab)
+	ret.metrics = recordCreated(ObjectMeCab)
+	return ret
+}
+
+func finalizeMeCab(m *mecab) {
+	if m.mecab != nil {
+		m.mecab.destroy()
+		recordDestroyed(ObjectMeCab, m.metrics)
+	}
+	m.mecab = nil
+}
+
+func (t *tagger) destroy() {
+	if t.own {
+		t.model.destroy()
+	}
+}
+
+// parse parses s with the lattice of the tagger.
+func (t *tagger) parse(op, s string) error {
+	t.lattice.SetSentence(s)
+	t.lattice.SetRequestType(t.model.requestType())
+	if !t.model.parse(t.lattice) {
+		t.err = t.lattice.What()
+		return classifyError(op, t.err, nil)
+	}
+	t.err = ""
+	return nil
+}
+
+// New returns new MeCab parser.
+func New(args map[string]string) (MeCab, error) {
+	vm, err := viterbi.Open(args)
+	if err != nil {
+		return MeCab{}, classifyError("New", err.Error(), args)
+	}
+	m := &model{model: vm}
+	return MeCab{
+		m: newMeCab(&tagger{
+			model:   m,
+			own:     true,
+			lattice: vm.NewLattice(),
+		}),
+	}, nil
+}
+
+// Destroy frees the MeCab parser.
+func (m MeCab) Destroy() {
+	runtime.SetFinalizer(m.m, nil) // clear the finalizer
+	if m.m.mecab != nil {
+		m.m.mecab.destroy()
+		recordDestroyed(ObjectMeCab, m.m.metrics)
+	}
+	m.m.mecab = nil
+}
+
+// Parse parses the string and returns the result as string.
+// Parse is not safe for concurrent use by multiple goroutines.
+func (m MeCab) Parse(s string) (string, error) {
+	if m.m.mecab == nil {
+		panic(errMeCabNotAvailable)
+	}
+	s, err := checkInput("Parse", s, m.m.policy)
+	if err != nil {
+		return "", err
+	}
+
+	m.m.guard.enter("Parse")
+	defer m.m.guard.exit()
+
+	obs := observeParse("Parse", len(s))
+	if err := m.m.mecab.parse("Parse", s); err != nil {
+		obs.done(Node{}, err)
+		return "", err
+	}
+	result, err := m.m.mecab.lattice.String()
+	if err != nil {
+		m.m.mecab.err = err.Error()
+		err := classifyError("Parse", err.Error(), nil)
+		obs.done(Node{}, err)
+		return "", err
+	}
+	obs.done(Node{}, nil)
+	return truncateAtNUL(result), nil
+}
+
+// truncateAtNUL truncates s at the first NUL byte,
+// as libmecab returns the result as a NUL-terminated string.
+func truncateAtNUL(s string) string {
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// ParseLattice parses the lattice and returns the result as string.
+// ParseLattice is safe for concurrent use by multiple goroutines.
+// Create a lattice for each goroutine.
+func (m MeCab) ParseLattice(lattice Lattice) error {
+	if m.m.mecab == nil {
+		panic(errMeCabNotAvailable)
+	}
+	if lattice.l.lattice == nil {
+		panic(errLatticeNotAvailable)
+	}
+	if m.m.policy != InputPassThrough {
+		// the sentence is set by SetSentence, and it is not checked yet.
+		s := lattice.Sentence()
+		checked, err := checkInput("ParseLattice", s, m.m.policy)
+		if err != nil {
+			return err
+		}
+		if checked != s {
+			lattice.setSentence(checked)
+		}
+	}
+
+	var obs parseObserver
+	if getMetrics() != nil {
+		obs = observeParse("ParseLattice", lattice.Size())
+	}
+	if !m.m.mecab.model.parse(lattice.l.lattice) {
+		err := classifyError("ParseLattice", lattice.l.lattice.What(), nil)
+		obs.done(Node{}, err)
+		return err
+	}
+	lattice.l.tagger = m.m
+	obs.done(lattice.BOSNode(), nil)
+	return nil
+}
+
+// ParseToNode parses the string and returns the result as [Node].
+// ParseToNode is not safe for concurrent use by multiple goroutines.
+func (m MeCab) ParseToNode(s string) (Node, error) {
+	if m.m.mecab == nil {
+		panic(errMeCabNotAvailable)
+	}
+	s, err := checkInput("ParseToNode", s, m.m.policy)
+	if err != nil {
+		return Node{}, err
+	}
+
+	m.m.guard.enter("ParseToNode")
+	defer m.m.guard.exit()
+
+	obs := observeParse("ParseToNode", len(s))
+	if err := m.m.mecab.parse("ParseToNode", s); err != nil {
+		obs.done(Node{}, err)
+		return Node{}, err
+	}
+	node := Node{
+		node:  m.m.mecab.lattice.BOSNode(),
+		mecab: m.m,
+		guard: m.m.guard.nodeGuard(),
+	}
+	obs.done(node, nil)
+	return node, nil
+}
+
+// Error returns the error of MeCab.
+func (m MeCab) Error() error {
+	if m.m.mecab == nil {
+		panic(errMeCabNotAvailable)
+	}
+	if m.m.mecab.err == "" {
+		return nil
+	}
+	return classifyError("", m.m.mecab.err, nil)
+}
@@ -1,10 +1,14 @@
 package mecab
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/shogo82148/go-mecab/internal/dictest"
 )
 
 var mecabrcPath string
@@ -23,7 +27,80 @@ func rcfile(config map[string]string) map[string]string {
 	return config
 }
 
+// buildPureGoDictionary writes a small dictionary without mecab-dict-index.
+// The entries are encoded in charset if it is supported.
+func buildPureGoDictionary(t *testing.T, charset string) map[string]string {
+	t.Helper()
+	dir := t.TempDir()
+	categories := []dictest.Category{
+		{Name: "DEFAULT", Group: true},
+		{Name: "SPACE", Group: true},
+		{Name: "KANJI", Length: 2},
+	}
+	category := func(r rune) []int {
+		switch {
+		case r == ' ':
+			return []int{1}
+		case 0x4e00 <= r && r <= 0x9fff:
+			return []int{2}
+		}
+		return []int{0}
+	}
+	sys := []dictest.Entry{
+		{Surface: "東京", LCAttr: 1, RCAttr: 1, PosID: 1, WCost: 2900, Feature: "名詞,固有名詞,トウキョウ"},
+		{Surface: "東京都", LCAttr: 1, RCAttr: 1, PosID: 1, WCost: 5100, Feature: "名詞,固有名詞,トウキョウト"},
+		{Surface: "都", LCAttr: 1, RCAttr: 1, PosID: 2, WCost: 2000, Feature: "名詞,接尾,ト"},
+	}
+	unk := []dictest.Entry{
+		{Surface: "DEFAULT", LCAttr: 1, RCAttr: 1, WCost: 9000, Feature: "記号,一般,*"},
+		{Surface: "SPACE", LCAttr: 1, RCAttr: 1, WCost: 9000, Feature: "記号,空白,*"},
+		{Surface: "KANJI", LCAttr: 1, RCAttr: 1, WCost: 8000, Feature: "名詞,一般,*"},
+	}
+	if c, err := newCodec("buildPureGoDictionary", charset); err == nil && c != nil {
+		for _, entries := range [][]dictest.Entry{sys, unk} {
+			for i := range entries {
+				entries[i].Surface = mustEncode(t, c, entries[i].Surface)
+				entries[i].Feature = mustEncode(t, c, entries[i].Feature)
+			}
+		}
+	}
+	err := dictest.WriteFiles(dir, map[string][]byte{
+		"sys.dic":    dictest.Dictionary(0, 2, 2, charset, sys),
+		"unk.dic":    dictest.Dictionary(2, 2, 2, charset, unk),
+		"matrix.bin": dictest.Matrix(2, 2, func(rcAttr, lcAttr int) int16 { return 100 }),
+		"char.bin":   dictest.CharProperty(categories, category),
+		"dicrc":      []byte("bos-feature = BOS/EOS,*,*\n"),
+		"mecabrc":    nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]string{
+		"rcfile": filepath.Join(dir, "mecabrc"),
+		"dicdir": dir,
+	}
+}
+
+func mustEncode(t *testing.T, c *codec, s string) string {
+	t.Helper()
+	ret, err := c.encode("mustEncode", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+// requireLibMeCab skips the test if it is built without libmecab.
+func requireLibMeCab(t *testing.T) {
+	t.Helper()
+	if !libmecab {
+		t.Skip("requires libmecab, but it is built without cgo")
+	}
+}
+
 func TestNewMeCab(t *testing.T) {
+	// all-morphs is not supported by the pure Go implementation.
+	requireLibMeCab(t)
 	mecab, err := New(rcfile(map[string]string{
 		"output-format-type": "wakati",
 		"all-morphs":         "",
@@ -46,6 +123,13 @@ func TestNewMeCab_error(t *testing.T) {
 	if !strings.Contains(err.Error(), "unknown format type [unknown format]") {
 		t.Errorf("want %q error, got %q", "unknown format type [unknown format]", err.Error())
 	}
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("want ErrInvalidOption, got %v", err)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Op != "New" {
+		t.Errorf("want *Error with op %q, got %#v", "New", err)
+	}
 }
 
 func TestParse(t *testing.T) {
@@ -0,0 +1,325 @@
+package mecab
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// ObjectKind is a kind of the objects that hold resources of MeCab.
+type ObjectKind int
+
+const (
+	// ObjectMeCab is the kind for [MeCab].
+	ObjectMeCab ObjectKind = iota
+
+	// ObjectLattice is the kind for [Lattice].
+	ObjectLattice
+
+	// ObjectModel is the kind for [Model].
+	ObjectModel
+
+	numObjectKinds
+)
+
+func (kind ObjectKind) String() string {
+	switch kind {
+	case ObjectMeCab:
+		return "MeCab"
+	case ObjectLattice:
+		return "Lattice"
+	case ObjectModel:
+		return "Model"
+	}
+	return ""
+}
+
+// ParseStats is statistics of a parse call.
+type ParseStats struct {
+	// Op is the name of the method, e.g. "Parse", "ParseToNode" or "ParseLattice".
+	Op string
+
+	// Bytes is the length of the input in bytes, as it is passed to MeCab.
+	// If the charset of the dictionary is not UTF-8, it is the length of the encoded input,
+	// not the length of the UTF-8 string.
+	Bytes int
+
+	// Tokens is the number of the morphs in the result, excluding BOS and EOS.
+	// It is always zero for Parse and ParseToString,
+	// because they don't return nodes.
+	Tokens int
+
+	// UnknownTokens is the number of the unknown morphs in the result.
+	UnknownTokens int
+
+	// Duration is the time spent in MeCab.
+	Duration time.Duration
+
+	// Err is the error of the parse call.
+	Err error
+}
+
+// Metrics is a hook for recording the activity of MeCab.
+// The methods may be called concurrently from multiple goroutines and from finalizers,
+// so they must be safe for concurrent use and must not block.
+type Metrics interface {
+	// ObjectCreated is called when a MeCab, a Lattice or a Model is created.
+	ObjectCreated(kind ObjectKind)
+
+	// ObjectDestroyed is called when a MeCab, a Lattice or a Model is destroyed,
+	// by Destroy or by the finalizer.
+	// It is called on the hook that recorded the creation of the object,
+	// even if SetMetrics has replaced the hook since then.
+	ObjectDestroyed(kind ObjectKind)
+
+	// ParseDone is called when a parse call finishes.
+	ParseDone(stats ParseStats)
+}
+
+type metricsHolder struct {
+	m Metrics
+}
+
+var currentMetrics atomic.Pointer[metricsHolder]
+
+// SetMetrics sets the hook for recording metrics.
+// Objects created before SetMetrics is called are not counted as live objects.
+// The destruction of an object is reported to the hook that recorded its creation,
+// so the objects created with the previous hook are still reported to it.
+// Passing nil disables recording of new objects and parse calls.
+func SetMetrics(m Metrics) {
+	if m == nil {
+		currentMetrics.Store(nil)
+		return
+	}
+	currentMetrics.Store(&metricsHolder{m: m})
+}
+
+func getMetrics() Metrics {
+	h := currentMetrics.Load()
+	if h == nil {
+		return nil
+	}
+	return h.m
+}
+
+// recordCreated records the creation of an object, and returns the hook that recorded it.
+// The result should be passed to recordDestroyed when the object is destroyed.
+func recordCreated(kind ObjectKind) Metrics {
+	m := getMetrics()
+	if m != nil {
+		m.ObjectCreated(kind)
+	}
+	return m
+}
+
+// recordDestroyed records the destruction of an object to m, the hook that recorded its creation.
+// The current hook is not used, so the live objects of each hook stay balanced
+// even if the hook is replaced or disabled while the object is alive.
+func recordDestroyed(kind ObjectKind, m Metrics) {
+	if m != nil {
+		m.ObjectDestroyed(kind)
+	}
+}
+
+// parseObserver measures a parse call.
+// It is zero if no metrics hook is set, and then it records nothing.
+type parseObserver struct {
+	m     Metrics
+	op    string
+	bytes int
+	start time.Time
+}
+
+func observeParse(op string, bytes int) parseObserver {
+	m := getMetrics()
+	if m == nil {
+		return parseObserver{}
+	}
+	return parseObserver{
+		m:     m,
+		op:    op,
+		bytes: bytes,
+		start: time.Now(),
+	}
+}
+
+// done records the result. node is the first node of the result, or a zero node.
+func (o parseObserver) done(node Node, err error) {
+	if o.m == nil {
+		return
+	}
+	stats := ParseStats{
+		Op:       o.op,
+		Bytes:    o.bytes,
+		Duration: time.Since(o.start),
+		Err:      err,
+	}
+	for ; !node.IsZero(); node = node.Next() {
+		switch node.Stat() {
+		case BOSNode, EOSNode:
+			continue
+		case UnknownNode:
+			stats.UnknownTokens++
+		}
+		stats.Tokens++
+	}
+	o.m.ParseDone(stats)
+}
+
+// DefaultLatencyBuckets is the default upper bounds of the latency histogram of [MemoryMetrics].
+var DefaultLatencyBuckets = []time.Duration{
+	10 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// MemoryMetrics is an in-memory implementation of [Metrics].
+// It also implements [expvar.Var], so it can be published by [expvar.Publish].
+type MemoryMetrics struct {
+	parses        atomic.Int64
+	errors        atomic.Int64
+	bytes         atomic.Int64
+	tokens        atomic.Int64
+	unknownTokens atomic.Int64
+	created       [numObjectKinds]atomic.Int64
+	destroyed     [numObjectKinds]atomic.Int64
+
+	bounds  []time.Duration
+	buckets []atomic.Int64 // len(buckets) == len(bounds)+1, the last one is for overflow.
+	total   atomic.Int64   // total duration in nanoseconds.
+}
+
+// NewMemoryMetrics returns a new MemoryMetrics.
+// If bounds is nil, [DefaultLatencyBuckets] is used.
+// bounds must be sorted in increasing order.
+func NewMemoryMetrics(bounds []time.Duration) *MemoryMetrics {
+	if bounds == nil {
+		bounds = DefaultLatencyBuckets
+	}
+	bounds = append([]time.Duration(nil), bounds...)
+	return &MemoryMetrics{
+		bounds:  bounds,
+		buckets: make([]atomic.Int64, len(bounds)+1),
+	}
+}
+
+// ObjectCreated implements [Metrics].
+func (m *MemoryMetrics) ObjectCreated(kind ObjectKind) {
+	if kind < 0 || kind >= numObjectKinds {
+		return
+	}
+	m.created[kind].Add(1)
+}
+
+// ObjectDestroyed implements [Metrics].
+func (m *MemoryMetrics) ObjectDestroyed(kind ObjectKind) {
+	if kind < 0 || kind >= numObjectKinds {
+		return
+	}
+	m.destroyed[kind].Add(1)
+}
+
+// ParseDone implements [Metrics].
+func (m *MemoryMetrics) ParseDone(stats ParseStats) {
+	m.parses.Add(1)
+	if stats.Err != nil {
+		m.errors.Add(1)
+	}
+	m.bytes.Add(int64(stats.Bytes))
+	m.tokens.Add(int64(stats.Tokens))
+	m.unknownTokens.Add(int64(stats.UnknownTokens))
+	m.total.Add(int64(stats.Duration))
+
+	if len(m.buckets) == 0 {
+		// the zero value of MemoryMetrics doesn't have the histogram.
+		return
+	}
+	i := 0
+	for i < len(m.bounds) && stats.Duration > m.bounds[i] {
+		i++
+	}
+	m.buckets[i].Add(1)
+}
+
+// LatencyBucket is a bucket of the latency histogram.
+type LatencyBucket struct {
+	// UpperBound is the upper bound of the bucket.
+	// It is zero for the overflow bucket.
+	UpperBound time.Duration `json:"upper_bound"`
+
+	// Count is the number of the parse calls in the bucket.
+	// It is not cumulative.
+	Count int64 `json:"count"`
+}
+
+// MetricsSnapshot is a snapshot of [MemoryMetrics].
+type MetricsSnapshot struct {
+	Parses        int64 `json:"parses"`
+	Errors        int64 `json:"errors"`
+	Bytes         int64 `json:"bytes"`
+	Tokens        int64 `json:"tokens"`
+	UnknownTokens int64 `json:"unknown_tokens"`
+
+	// UnknownRatio is UnknownTokens / Tokens.
+	UnknownRatio float64 `json:"unknown_ratio"`
+
+	// TotalDuration is the sum of the durations of all parse calls.
+	TotalDuration time.Duration `json:"total_duration"`
+
+	Latency []LatencyBucket `json:"latency"`
+
+	// LiveMeCabs, LiveLattices and LiveModels are the number of the objects
+	// that are created but not destroyed yet.
+	LiveMeCabs   int64 `json:"live_mecabs"`
+	LiveLattices int64 `json:"live_lattices"`
+	LiveModels   int64 `json:"live_models"`
+}
+
+// Snapshot returns the current values.
+func (m *MemoryMetrics) Snapshot() MetricsSnapshot {
+	s := MetricsSnapshot{
+		Parses:        m.parses.Load(),
+		Errors:        m.errors.Load(),
+		Bytes:         m.bytes.Load(),
+		Tokens:        m.tokens.Load(),
+		UnknownTokens: m.unknownTokens.Load(),
+		TotalDuration: time.Duration(m.total.Load()),
+		Latency:       make([]LatencyBucket, len(m.buckets)),
+		LiveMeCabs:    m.live(ObjectMeCab),
+		LiveLattices:  m.live(ObjectLattice),
+		LiveModels:    m.live(ObjectModel),
+	}
+	if s.Tokens > 0 {
+		s.UnknownRatio = float64(s.UnknownTokens) / float64(s.Tokens)
+	}
+	for i := range m.buckets {
+		if i < len(m.bounds) {
+			s.Latency[i].UpperBound = m.bounds[i]
+		}
+		s.Latency[i].Count = m.buckets[i].Load()
+	}
+	return s
+}
+
+func (m *MemoryMetrics) live(kind ObjectKind) int64 {
+	return m.created[kind].Load() - m.destroyed[kind].Load()
+}
+
+// String returns the snapshot in JSON format.
+// It implements [expvar.Var].
+func (m *MemoryMetrics) String() string {
+	data, err := json.Marshal(m.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
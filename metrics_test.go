@@ -0,0 +1,148 @@
+package mecab
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryMetrics(t *testing.T) {
+	m := NewMemoryMetrics([]time.Duration{time.Millisecond, time.Second})
+	m.ObjectCreated(ObjectMeCab)
+	m.ObjectCreated(ObjectMeCab)
+	m.ObjectDestroyed(ObjectMeCab)
+	m.ObjectCreated(ObjectLattice)
+	m.ParseDone(ParseStats{
+		Op:            "ParseToNode",
+		Bytes:         21,
+		Tokens:        4,
+		UnknownTokens: 1,
+		Duration:      500 * time.Microsecond,
+	})
+	m.ParseDone(ParseStats{
+		Op:       "Parse",
+		Bytes:    10,
+		Duration: 2 * time.Second,
+		Err:      errors.New("error"),
+	})
+
+	s := m.Snapshot()
+	if s.Parses != 2 {
+		t.Errorf("want 2 parses, got %d", s.Parses)
+	}
+	if s.Errors != 1 {
+		t.Errorf("want 1 error, got %d", s.Errors)
+	}
+	if s.Bytes != 31 {
+		t.Errorf("want 31 bytes, got %d", s.Bytes)
+	}
+	if s.UnknownRatio != 0.25 {
+		t.Errorf("want 0.25 unknown ratio, got %f", s.UnknownRatio)
+	}
+	if s.LiveMeCabs != 1 || s.LiveLattices != 1 || s.LiveModels != 0 {
+		t.Errorf("unexpected live objects: %d, %d, %d", s.LiveMeCabs, s.LiveLattices, s.LiveModels)
+	}
+	want := []LatencyBucket{
+		{UpperBound: time.Millisecond, Count: 1},
+		{UpperBound: time.Second, Count: 0},
+		{UpperBound: 0, Count: 1},
+	}
+	if len(s.Latency) != len(want) {
+		t.Fatalf("want %d buckets, got %d", len(want), len(s.Latency))
+	}
+	for i := range want {
+		if s.Latency[i] != want[i] {
+			t.Errorf("bucket %d: want %v, got %v", i, want[i], s.Latency[i])
+		}
+	}
+
+	var decoded MetricsSnapshot
+	if err := json.Unmarshal([]byte(m.String()), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Parses != 2 {
+		t.Errorf("want 2 parses, got %d", decoded.Parses)
+	}
+}
+
+func TestMemoryMetrics_zero(t *testing.T) {
+	var m MemoryMetrics
+	m.ParseDone(ParseStats{Duration: time.Second})
+	if s := m.Snapshot(); s.Parses != 1 {
+		t.Errorf("want 1 parse, got %d", s.Parses)
+	}
+}
+
+func TestSetMetrics_createdBefore(t *testing.T) {
+	lattice, err := NewLattice()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := NewMemoryMetrics(nil)
+	SetMetrics(m)
+	defer SetMetrics(nil)
+
+	lattice.Destroy()
+	if s := m.Snapshot(); s.LiveLattices != 0 {
+		t.Errorf("want 0 live lattices, got %d", s.LiveLattices)
+	}
+}
+
+func TestSetMetrics(t *testing.T) {
+	m := NewMemoryMetrics(nil)
+	SetMetrics(m)
+	defer SetMetrics(nil)
+
+	mecab, err := New(rcfile(map[string]string{}))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	if s := m.Snapshot(); s.LiveMeCabs != 1 {
+		t.Errorf("want 1 live mecab, got %d", s.LiveMeCabs)
+	}
+
+	if _, err := mecab.ParseToNode("こんにちは世界"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	s := m.Snapshot()
+	if s.Parses != 1 {
+		t.Errorf("want 1 parse, got %d", s.Parses)
+	}
+	if s.Bytes != int64(len("こんにちは世界")) {
+		t.Errorf("want %d bytes, got %d", len("こんにちは世界"), s.Bytes)
+	}
+	if s.Tokens != 2 {
+		t.Errorf("want 2 tokens, got %d", s.Tokens)
+	}
+
+	mecab.Destroy()
+	if s := m.Snapshot(); s.LiveMeCabs != 0 {
+		t.Errorf("want 0 live mecab, got %d", s.LiveMeCabs)
+	}
+}
+
+func TestSetMetrics_replaced(t *testing.T) {
+	m1 := NewMemoryMetrics(nil)
+	SetMetrics(m1)
+	defer SetMetrics(nil)
+
+	lattice, err := NewLattice()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m2 := NewMemoryMetrics(nil)
+	SetMetrics(m2)
+	lattice.Destroy()
+
+	if s := m1.Snapshot(); s.LiveLattices != 0 {
+		t.Errorf("want 0 live lattices in the old hook, got %d", s.LiveLattices)
+	}
+	if s := m2.Snapshot(); s.LiveLattices != 0 {
+		t.Errorf("want 0 live lattices in the new hook, got %d", s.LiveLattices)
+	}
+}
@@ -0,0 +1,178 @@
+//go:build cgo && !purego
+
+package mecab
+
+// #include <mecab.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// to introduce garbage-collection while maintaining backwards compatibility.
+type model struct {
+	model *C.mecab_model_t
+
+	// args is the arguments of NewModel.
+	args map[string]string
+
+	// tempDirs are removed when the model is destroyed.
+	tempDirs []string
+
+	// files holds the dictionary files opened by the methods of Model, e.g. CommonPrefixSearch.
+	files dictionaryFiles
+
+	// metrics is the hook that recorded the creation, or nil.
+	// The destruction is reported to the same hook.
+	metrics Metrics
+}
+
+func newModel(m *C.mecab_model_t) *model {
+	ret := &model{
+		model: m,
+	}
+	runtime.SetFinalizer(ret, finalizeModel)
+	ret.metrics = recordCreated(ObjectModel)
+	return ret
+}
+
+func finalizeModel(m *model) {
+	if m.model != nil {
+		C.mecab_model_destroy(m.model)
+		recordDestroyed(ObjectModel, m.metrics)
+	}
+	m.model = nil
+	m.files.close()
+	m.removeTempDirs()
+}
+
+// NewModel returns a new model.
+func NewModel(args map[string]string) (Model, error) {
+	// build the argument
+	opts := make([]*C.char, 0, len(args)+1)
+	opt := C.CString("mecab")
+	defer C.free(unsafe.Pointer(opt))
+	opts = append(opts, opt)
+	opt = C.CString("--allocate-sentence")
+	defer C.free(unsafe.Pointer(opt))
+	opts = append(opts, opt)
+	for k, v := range args {
+		var goopt string
+		if v != "" {
+			goopt = fmt.Sprintf("--%s=%s", k, v)
+		} else {
+			goopt = "--" + k
+		}
+		opt := C.CString(goopt)
+		defer C.free(unsafe.Pointer(opt))
+		opts = append(opts, opt)
+	}
+
+	// C.mecab_model_new sets an error in the thread local storage.
+	// so C.mecab_model_new and C.mecab_strerror must be call in same thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// create new MeCab model
+	m := C.mecab_model_new(C.int(len(opts)), (**C.char)(&opts[0]))
+	if m == nil {
+		return Model{}, newError("NewModel", nil, args)
+	}
+
+	ret := Model{
+		m: newModel(m),
+	}
+	ret.m.args = make(map[string]string, len(args))
+	for k, v := range args {
+		ret.m.args[k] = v
+	}
+	return ret, nil
+}
+
+// Destroy frees the model.
+func (m Model) Destroy() {
+	runtime.SetFinalizer(m.m, nil) // clear the finalizer
+	if m.m.model != nil {
+		C.mecab_model_destroy(m.m.model)
+		recordDestroyed(ObjectModel, m.m.metrics)
+	}
+	m.m.model = nil
+	m.m.files.close()
+	m.m.removeTempDirs()
+}
+
+// NewMeCab returns a new mecab.
+func (m Model) NewMeCab() (MeCab, error) {
+	if m.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+
+	// C.mecab_model_new_tagger sets an error in the thread local storage.
+	// so C.mecab_model_new_tagger and C.mecab_strerror must be call in same thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	mm := C.mecab_model_new_tagger(m.m.model)
+	if mm == nil {
+		return MeCab{}, newError("NewMeCab", nil, nil)
+	}
+	runtime.KeepAlive(m.m)
+	ret := MeCab{m: newMeCab(mm)}
+	if err := ret.m.initCodec("NewMeCab"); err != nil {
+		ret.Destroy()
+		return MeCab{}, err
+	}
+	return ret, nil
+}
+
+// NewLattice returns a new lattice.
+func (m Model) NewLattice() (Lattice, error) {
+	if m.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+
+	// C.mecab_model_new_lattice sets an error in the thread local storage.
+	// so C.mecab_model_new_lattice and C.mecab_strerror must be call in same thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	lattice := C.mecab_model_new_lattice(m.m.model)
+	if lattice == nil {
+		return Lattice{}, newError("NewLattice", nil, nil)
+	}
+	return Lattice{l: newLattice(lattice)}, nil
+}
+
+// Swap replaces the model by the other model.
+// m2 is consumed by Swap even if it fails, and it must not be used after that.
+// Calling Destroy of m2 is allowed and does nothing.
+func (m Model) Swap(m2 Model) error {
+	if m.m.model == nil || m2.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+
+	// C.mecab_model_swap sets an error in the thread local storage.
+	// so C.mecab_model_swap and C.mecab_strerror must be call in same thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// mecab_model_swap takes the ownership of m2, and deletes it even if it fails.
+	C.mecab_model_swap(m.m.model, m2.m.model)
+	err := newError("Swap", nil, nil)
+	runtime.SetFinalizer(m2.m, nil) // clear the finalizer
+	m2.m.model = nil
+	recordDestroyed(ObjectModel, m2.m.metrics)
+	m2.m.files.close()
+	if err == nil {
+		// the dictionaries of m2, e.g. the temporary user dictionary, are used by m now.
+		m.m.tempDirs = append(m.m.tempDirs, m2.m.tempDirs...)
+		m2.m.tempDirs = nil
+		// the arguments of m2 are used by Model.WithUserWords to rebuild m.
+		m.m.args = m2.m.args
+	} else {
+		m2.m.removeTempDirs()
+	}
+	return err
+}
@@ -0,0 +1,163 @@
+//go:build !cgo || purego
+
+package mecab
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/shogo82148/go-mecab/internal/viterbi"
+)
+
+// to introduce garbage-collection while maintaining backwards compatibility.
+type model struct {
+	// mu guards model, because Swap replaces it while the taggers parse.
+	mu    sync.RWMutex
+	model *viterbi.Model
+
+	// args is the arguments of NewModel.
+	args map[string]string
+
+	// tempDirs are removed when the model is destroyed.
+	tempDirs []string
+
+	// files holds the dictionary files opened by the methods of Model, e.g. CommonPrefixSearch.
+	files dictionaryFiles
+
+	// metrics is the hook that recorded the creation, or nil.
+	// The destruction is reported to the same hook.
+	metrics Metrics
+}
+
+func newModel(m *viterbi.Model) *model {
+	ret := &model{
+		model: m,
+	}
+	runtime.SetFinalizer(ret, finalizeModel)
+	ret.metrics = recordCreated(ObjectModel)
+	return ret
+}
+
+func finalizeModel(m *model) {
+	if m.model != nil {
+		m.model.Close()
+		recordDestroyed(ObjectModel, m.metrics)
+	}
+	m.model = nil
+	m.files.close()
+	m.removeTempDirs()
+}
+
+func (m *model) destroy() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.model != nil {
+		m.model.Close()
+	}
+	m.model = nil
+}
+
+func (m *model) requestType() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.model == nil {
+		return int(RequestTypeOneBest)
+	}
+	return m.model.RequestType()
+}
+
+func (m *model) parse(l *viterbi.Lattice) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.model == nil {
+		l.SetWhat("model is not available")
+		return false
+	}
+	return m.model.Parse(l)
+}
+
+// NewModel returns a new model.
+func NewModel(args map[string]string) (Model, error) {
+	m, err := viterbi.Open(args)
+	if err != nil {
+		return Model{}, classifyError("NewModel", err.Error(), args)
+	}
+
+	ret := Model{
+		m: newModel(m),
+	}
+	ret.m.args = make(map[string]string, len(args))
+	for k, v := range args {
+		ret.m.args[k] = v
+	}
+	return ret, nil
+}
+
+// Destroy frees the model.
+func (m Model) Destroy() {
+	runtime.SetFinalizer(m.m, nil) // clear the finalizer
+	if m.m.model != nil {
+		recordDestroyed(ObjectModel, m.m.metrics)
+	}
+	m.m.destroy()
+	m.m.files.close()
+	m.m.removeTempDirs()
+}
+
+// NewMeCab returns a new mecab.
+func (m Model) NewMeCab() (MeCab, error) {
+	if m.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+	m.m.mu.RLock()
+	l := m.m.model.NewLattice()
+	m.m.mu.RUnlock()
+	return MeCab{
+		m: newMeCab(&tagger{
+			model:   m.m,
+			lattice: l,
+		}),
+	}, nil
+}
+
+// NewLattice returns a new lattice.
+func (m Model) NewLattice() (Lattice, error) {
+	m.m.mu.RLock()
+	defer m.m.mu.RUnlock()
+	if m.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+	return Lattice{l: newLattice(m.m.model.NewLattice())}, nil
+}
+
+// Swap replaces the model by the other model.
+// m2 is consumed by Swap even if it fails, and it must not be used after that.
+// Calling Destroy of m2 is allowed and does nothing.
+func (m Model) Swap(m2 Model) error {
+	if m.m.model == nil || m2.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+
+	m2.m.mu.Lock()
+	next := m2.m.model
+	m2.m.model = nil
+	m2.m.mu.Unlock()
+	runtime.SetFinalizer(m2.m, nil) // clear the finalizer
+	// m2 doesn't hold the dictionaries any more.
+	recordDestroyed(ObjectModel, m2.m.metrics)
+	m2.m.files.close()
+
+	// the dictionaries of m2, e.g. the temporary user dictionary, are used by m now.
+	m.m.tempDirs = append(m.m.tempDirs, m2.m.tempDirs...)
+	m2.m.tempDirs = nil
+	// the arguments of m2 are used by Model.WithUserWords to rebuild m.
+	m.m.args = m2.m.args
+
+	m.m.mu.Lock()
+	current := m.m.model
+	m.m.model = next
+	m.m.mu.Unlock()
+
+	current.Close()
+	return nil
+}
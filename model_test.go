@@ -1,6 +1,7 @@
 package mecab
 
 import (
+	"errors"
 	"runtime"
 	"strings"
 	"testing"
@@ -45,6 +46,13 @@ func TestNewModel_error(t *testing.T) {
 	if !strings.Contains(err.Error(), "unknown format type [unknown format]") {
 		t.Errorf("want %q error, got %q", "unknown format type [unknown format]", err.Error())
 	}
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("want ErrInvalidOption, got %v", err)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Op != "NewModel" {
+		t.Errorf("want *Error with op %q, got %#v", "NewModel", err)
+	}
 }
 
 func TestModelFinalizer(t *testing.T) {
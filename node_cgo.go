@@ -0,0 +1,175 @@
+//go:build cgo && !purego
+
+package mecab
+
+// #include <mecab.h>
+// #include <stdlib.h>
+import "C"
+
+// Node is a node in a lattice.
+type Node struct {
+	node *C.mecab_node_t
+
+	// actual data of node is stored in mecab or lattice.
+	// they are here to avoid garbage collection.
+	mecab   *mecab
+	lattice *lattice
+
+	// guard detects use of the node after a new parse in debug mode.
+	guard nodeGuard
+}
+
+// Surface returns the surface string.
+func (node Node) Surface() string {
+	node.guard.check(node.mecab)
+	s := C.GoStringN(node.node.surface, C.int(node.node.length))
+	if c := node.codec(); c != nil {
+		return c.decode(s)
+	}
+	return s
+}
+
+// Feature returns the feature.
+func (node Node) Feature() string {
+	node.guard.check(node.mecab)
+	s := C.GoString(node.node.feature)
+	if c := node.codec(); c != nil {
+		return c.decode(s)
+	}
+	return s
+}
+
+// Length returns the length of the surface string in UTF-8.
+func (node Node) Length() int {
+	node.guard.check(node.mecab)
+	if node.codec() != nil {
+		return len(node.Surface())
+	}
+	return int(node.node.length)
+}
+
+// RLength returns the length of the surface string including white space before the morph in UTF-8.
+func (node Node) RLength() int {
+	node.guard.check(node.mecab)
+	if node.codec() != nil {
+		// white spaces are ASCII characters, so their length is same in any charset.
+		return int(node.node.rlength) - int(node.node.length) + len(node.Surface())
+	}
+	return int(node.node.rlength)
+}
+
+// PosID returns the part-of-speech id.
+func (node Node) PosID() int {
+	node.guard.check(node.mecab)
+	return int(node.node.posid)
+}
+
+// Prev returns the previous Node.
+func (node Node) Prev() Node {
+	node.guard.check(node.mecab)
+	return Node{
+		node:    (*C.mecab_node_t)(node.node.prev),
+		mecab:   node.mecab,
+		lattice: node.lattice,
+		guard:   node.guard,
+	}
+}
+
+// Next returns the next Node.
+func (node Node) Next() Node {
+	node.guard.check(node.mecab)
+	return Node{
+		node:    (*C.mecab_node_t)(node.node.next),
+		mecab:   node.mecab,
+		lattice: node.lattice,
+		guard:   node.guard,
+	}
+}
+
+// ENext returns a node which ends same position
+func (node Node) ENext() Node {
+	node.guard.check(node.mecab)
+	return Node{
+		node:    (*C.mecab_node_t)(node.node.enext),
+		mecab:   node.mecab,
+		lattice: node.lattice,
+		guard:   node.guard,
+	}
+}
+
+// BNext returns a node which begins same position
+func (node Node) BNext() Node {
+	node.guard.check(node.mecab)
+	return Node{
+		node:    (*C.mecab_node_t)(node.node.bnext),
+		mecab:   node.mecab,
+		lattice: node.lattice,
+		guard:   node.guard,
+	}
+}
+
+// Stat returns the type of Node.
+func (node Node) Stat() NodeStat {
+	node.guard.check(node.mecab)
+	return NodeStat(node.node.stat)
+}
+
+// ID returns the id of Node.
+func (node Node) ID() int {
+	node.guard.check(node.mecab)
+	return int(node.node.id)
+}
+
+// RCAttr returns the right context attribute.
+func (node Node) RCAttr() int {
+	node.guard.check(node.mecab)
+	return int(node.node.rcAttr)
+}
+
+// LCAttr returns the right context attribute.
+func (node Node) LCAttr() int {
+	node.guard.check(node.mecab)
+	return int(node.node.lcAttr)
+}
+
+// CharType returns the character type.
+func (node Node) CharType() int {
+	node.guard.check(node.mecab)
+	return int(node.node.char_type)
+}
+
+// IsBest returns that if the Node is the best solution.
+func (node Node) IsBest() bool {
+	node.guard.check(node.mecab)
+	return node.node.isbest != 0
+}
+
+// Alpha returns the forward accumulative log summation.
+func (node Node) Alpha() float32 {
+	node.guard.check(node.mecab)
+	return float32(node.node.alpha)
+}
+
+// Beta returns the backward accumulative log summation.
+func (node Node) Beta() float32 {
+	node.guard.check(node.mecab)
+	return float32(node.node.beta)
+}
+
+// Prob returns the marginal probability.
+func (node Node) Prob() float32 {
+	node.guard.check(node.mecab)
+	return float32(node.node.prob)
+}
+
+// WCost returns word cost.
+func (node Node) WCost() int {
+	node.guard.check(node.mecab)
+	return int(node.node.wcost)
+}
+
+// Cost returns the best accumulative cost from bos node to this node.
+func (node Node) Cost() int {
+	node.guard.check(node.mecab)
+	return int(node.node.cost)
+}
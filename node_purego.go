@@ -0,0 +1,161 @@
+//go:build !cgo || purego
+
+package mecab
+
+import "github.com/shogo82148/go-mecab/internal/viterbi"
+
+// Node is a node in a lattice.
+type Node struct {
+	node *viterbi.Node
+
+	// actual data of node is stored in mecab or lattice.
+	// they are here to avoid garbage collection.
+	mecab   *mecab
+	lattice *lattice
+
+	// guard detects use of the node after a new parse in debug mode.
+	guard nodeGuard
+}
+
+// Surface returns the surface string.
+func (node Node) Surface() string {
+	node.guard.check(node.mecab)
+	return node.node.Surface()
+}
+
+// Feature returns the feature.
+func (node Node) Feature() string {
+	node.guard.check(node.mecab)
+	return node.node.Feature
+}
+
+// Length returns the length of the surface string in UTF-8.
+func (node Node) Length() int {
+	node.guard.check(node.mecab)
+	return node.node.Length
+}
+
+// RLength returns the length of the surface string including white space before the morph in UTF-8.
+func (node Node) RLength() int {
+	node.guard.check(node.mecab)
+	return node.node.RLength
+}
+
+// PosID returns the part-of-speech id.
+func (node Node) PosID() int {
+	node.guard.check(node.mecab)
+	return int(node.node.PosID)
+}
+
+// Prev returns the previous Node.
+func (node Node) Prev() Node {
+	node.guard.check(node.mecab)
+	return Node{
+		node:    node.node.Prev,
+		mecab:   node.mecab,
+		lattice: node.lattice,
+		guard:   node.guard,
+	}
+}
+
+// Next returns the next Node.
+func (node Node) Next() Node {
+	node.guard.check(node.mecab)
+	return Node{
+		node:    node.node.Next,
+		mecab:   node.mecab,
+		lattice: node.lattice,
+		guard:   node.guard,
+	}
+}
+
+// ENext returns a node which ends same position
+func (node Node) ENext() Node {
+	node.guard.check(node.mecab)
+	return Node{
+		node:    node.node.ENext,
+		mecab:   node.mecab,
+		lattice: node.lattice,
+		guard:   node.guard,
+	}
+}
+
+// BNext returns a node which begins same position
+func (node Node) BNext() Node {
+	node.guard.check(node.mecab)
+	return Node{
+		node:    node.node.BNext,
+		mecab:   node.mecab,
+		lattice: node.lattice,
+		guard:   node.guard,
+	}
+}
+
+// Stat returns the type of Node.
+func (node Node) Stat() NodeStat {
+	node.guard.check(node.mecab)
+	return NodeStat(node.node.Stat)
+}
+
+// ID returns the id of Node.
+func (node Node) ID() int {
+	node.guard.check(node.mecab)
+	return node.node.ID
+}
+
+// RCAttr returns the right context attribute.
+func (node Node) RCAttr() int {
+	node.guard.check(node.mecab)
+	return int(node.node.RCAttr)
+}
+
+// LCAttr returns the right context attribute.
+func (node Node) LCAttr() int {
+	node.guard.check(node.mecab)
+	return int(node.node.LCAttr)
+}
+
+// CharType returns the character type.
+func (node Node) CharType() int {
+	node.guard.check(node.mecab)
+	return int(node.node.CharType)
+}
+
+// IsBest returns that if the Node is the best solution.
+func (node Node) IsBest() bool {
+	node.guard.check(node.mecab)
+	return node.node.IsBest
+}
+
+// Alpha returns the forward accumulative log summation.
+// It is always 0, because the pure Go implementation doesn't support [RequestTypeMarginalProb].
+func (node Node) Alpha() float32 {
+	node.guard.check(node.mecab)
+	return 0
+}
+
+// Beta returns the backward accumulative log summation.
+// It is always 0, because the pure Go implementation doesn't support [RequestTypeMarginalProb].
+func (node Node) Beta() float32 {
+	node.guard.check(node.mecab)
+	return 0
+}
+
+// Prob returns the marginal probability.
+// It is always 0, because the pure Go implementation doesn't support [RequestTypeMarginalProb].
+func (node Node) Prob() float32 {
+	node.guard.check(node.mecab)
+	return 0
+}
+
+// WCost returns word cost.
+func (node Node) WCost() int {
+	node.guard.check(node.mecab)
+	return int(node.node.WCost)
+}
+
+// Cost returns the best accumulative cost from bos node to this node.
+func (node Node) Cost() int {
+	node.guard.check(node.mecab)
+	return int(node.node.Cost)
+}
@@ -0,0 +1,219 @@
+package mecab
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Normalizer normalizes sentences before parsing.
+// It folds the width of characters and applies a few mappings for Japanese text,
+// and it keeps the mapping of the offsets to the original text.
+//
+// It is not NFKC. The compatibility characters other than the full-width and half-width forms,
+// e.g. "㈱", "①", "ﬁ" and "㌔", are not decomposed, and they are kept as they are.
+type Normalizer struct {
+	// FoldWidth folds full-width ASCII characters and the ideographic space to half-width,
+	// and half-width katakana to full-width.
+	FoldWidth bool
+
+	// ComposeVoicedMarks composes kana and the combining voiced sound marks,
+	// e.g. "か\u3099" to "が".
+	ComposeVoicedMarks bool
+
+	// UnifyWaveDash unifies the variants of the wave dash, e.g. "～" and "∼", to WaveDash.
+	UnifyWaveDash bool
+
+	// WaveDash is the unified wave dash. If it is zero, '〜' (U+301C) is used.
+	WaveDash rune
+
+	// UnifyLongVowel unifies the variants of the long vowel mark after kana,
+	// e.g. "―", "－" and "-", to "ー".
+	UnifyLongVowel bool
+}
+
+// DefaultNormalizer enables all normalizations.
+var DefaultNormalizer = &Normalizer{
+	FoldWidth:          true,
+	ComposeVoicedMarks: true,
+	UnifyWaveDash:      true,
+	UnifyLongVowel:     true,
+}
+
+// Normalized is a normalized sentence.
+type Normalized struct {
+	// Original is the original text.
+	Original string
+
+	// Text is the normalized text.
+	Text string
+
+	// orig[i] is the offset in Original of the byte i in Text.
+	// len(orig) == len(Text)+1.
+	orig []int
+}
+
+// OriginalOffset converts the byte offset in the normalized text
+// to the byte offset in the original text.
+func (n *Normalized) OriginalOffset(i int) int {
+	if i <= 0 {
+		return 0
+	}
+	if i >= len(n.orig) {
+		return len(n.Original)
+	}
+	return n.orig[i]
+}
+
+// Tokens returns a copy of the tokens with the offsets in the original text.
+// The surfaces of the tokens are still normalized,
+// and the original surface is n.Original[token.Start:token.End].
+func (n *Normalized) Tokens(tokens []Token) []Token {
+	ret := make([]Token, len(tokens))
+	for i, t := range tokens {
+		t.Start = n.OriginalOffset(t.Start)
+		t.End = n.OriginalOffset(t.End)
+		ret[i] = t
+	}
+	return ret
+}
+
+// SetNormalizedSentence normalizes the sentence and sets it in the lattice.
+// Use the returned [Normalized] to convert the offsets of the result into the original text.
+func (l Lattice) SetNormalizedSentence(s string, n *Normalizer) *Normalized {
+	normalized := n.Normalize(s)
+	l.SetSentence(normalized.Text)
+	return normalized
+}
+
+// Normalize normalizes s.
+func (n *Normalizer) Normalize(s string) *Normalized {
+	var buf strings.Builder
+	buf.Grow(len(s))
+	orig := make([]int, 0, len(s)+1)
+
+	prev := rune(-1)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size <= 1 {
+			// pass through invalid UTF-8 sequences.
+			buf.WriteByte(s[i])
+			orig = append(orig, i)
+			prev = r
+			i++
+			continue
+		}
+		end := i + size
+
+		out := n.normalizeRune(r, prev)
+		if n.ComposeVoicedMarks || n.FoldWidth {
+			if mark, size := utf8.DecodeRuneInString(s[end:]); n.isVoicedMark(mark) {
+				if composed, ok := composeVoiced(out, mark); ok {
+					out = composed
+					end += size
+				}
+			}
+		}
+
+		l := buf.Len()
+		buf.WriteRune(out)
+		for j := l; j < buf.Len(); j++ {
+			orig = append(orig, i)
+		}
+		prev = out
+		i = end
+	}
+	orig = append(orig, len(s))
+
+	return &Normalized{
+		Original: s,
+		Text:     buf.String(),
+		orig:     orig,
+	}
+}
+
+func (n *Normalizer) normalizeRune(r, prev rune) rune {
+	if n.UnifyWaveDash && isWaveDash(r) {
+		if n.WaveDash != 0 {
+			return n.WaveDash
+		}
+		return '〜'
+	}
+	if n.UnifyLongVowel && isLongVowelLike(r) && isKana(prev) {
+		return 'ー'
+	}
+	if n.FoldWidth {
+		switch {
+		case r == '　':
+			return ' '
+		case r >= '！' && r <= '～':
+			return r - 0xFEE0
+		case r >= '｡' && r <= '\uff9f':
+			return halfwidthKatakana[r-0xFF61]
+		}
+	}
+	return r
+}
+
+func (n *Normalizer) isVoicedMark(r rune) bool {
+	switch r {
+	case '\u3099', '\u309a':
+		return n.ComposeVoicedMarks
+	case '\uff9e', '\uff9f':
+		return n.FoldWidth
+	}
+	return false
+}
+
+// halfwidthKatakana is the full-width forms of U+FF61 - U+FF9F.
+var halfwidthKatakana = []rune("。「」、・ヲァィゥェォャュョッーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン゛゜")
+
+var (
+	voicedKana     = map[rune]rune{}
+	semiVoicedKana = map[rune]rune{}
+)
+
+func init() {
+	pairs := []rune("カガキギクグケゲコゴサザシジスズセゼソゾタダチヂツヅテデトドハバヒビフブヘベホボウヴ" +
+		"かがきぎくぐけげこごさざしじすずせぜそぞただちぢつづてでとどはばひびふぶへべほぼうゔ" +
+		"ワヷヰヸヱヹヲヺ")
+	for i := 0; i+1 < len(pairs); i += 2 {
+		voicedKana[pairs[i]] = pairs[i+1]
+	}
+	pairs = []rune("ハパヒピフプヘペホポはぱひぴふぷへぺほぽ")
+	for i := 0; i+1 < len(pairs); i += 2 {
+		semiVoicedKana[pairs[i]] = pairs[i+1]
+	}
+}
+
+func composeVoiced(r, mark rune) (rune, bool) {
+	var composed rune
+	var ok bool
+	switch mark {
+	case '\u3099', '\uff9e':
+		composed, ok = voicedKana[r]
+	case '\u309a', '\uff9f':
+		composed, ok = semiVoicedKana[r]
+	}
+	return composed, ok
+}
+
+func isWaveDash(r rune) bool {
+	switch r {
+	case '〜', '～', '∼', '∾', '〰':
+		return true
+	}
+	return false
+}
+
+func isLongVowelLike(r rune) bool {
+	switch r {
+	case '-', '‐', '‑', '‒', '–', '—', '―', '−', '－', 'ｰ', 'ー', '─', '━':
+		return true
+	}
+	return false
+}
+
+func isKana(r rune) bool {
+	return unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || r == 'ー'
+}
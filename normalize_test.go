@@ -0,0 +1,94 @@
+package mecab
+
+import (
+	"testing"
+)
+
+func TestNormalizer(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"ＰＣ", "PC"},
+		{"ＰＣ　１２３", "PC 123"},
+		{"ｶﾞｷﾞｸﾞｹﾞｺﾞ", "ガギグゲゴ"},
+		{"ﾊﾟﾋﾟﾌﾟ", "パピプ"},
+		{"か\u3099", "が"},
+		{"ﾃｰﾌﾞﾙ", "テーブル"},
+		{"ラ―メン", "ラーメン"},
+		{"ラ－メン", "ラーメン"},
+		{"A-B", "A-B"},
+		{"東京～大阪", "東京〜大阪"},
+		{"東京∼大阪", "東京〜大阪"},
+		{"\xffＡ", "\xffA"},
+		// it is not NFKC.
+		{"㈱①ﬁ㌔", "㈱①ﬁ㌔"},
+	}
+
+	for _, tt := range tests {
+		got := DefaultNormalizer.Normalize(tt.input)
+		if got.Text != tt.want {
+			t.Errorf("%q: want %q, got %q", tt.input, tt.want, got.Text)
+		}
+	}
+}
+
+func TestNormalizer_disabled(t *testing.T) {
+	n := &Normalizer{}
+	input := "ＰＣｶﾞ～"
+	if got := n.Normalize(input); got.Text != input {
+		t.Errorf("want %q, got %q", input, got.Text)
+	}
+
+	n = &Normalizer{UnifyWaveDash: true, WaveDash: '～'}
+	if got := n.Normalize("東京〜大阪"); got.Text != "東京～大阪" {
+		t.Errorf("want %q, got %q", "東京～大阪", got.Text)
+	}
+}
+
+func TestNormalized_Tokens(t *testing.T) {
+	input := "ＰＣとｶﾞｲﾄﾞ"
+	n := DefaultNormalizer.Normalize(input)
+	if n.Text != "PCとガイド" {
+		t.Fatalf("unexpected text: %q", n.Text)
+	}
+
+	tokens := n.Tokens([]Token{
+		{Surface: "PC", Start: 0, End: 2},
+		{Surface: "と", Start: 2, End: 5},
+		{Surface: "ガイド", Start: 5, End: 14},
+	})
+	want := []string{"ＰＣ", "と", "ｶﾞｲﾄﾞ"}
+	for i, token := range tokens {
+		if got := input[token.Start:token.End]; got != want[i] {
+			t.Errorf("%d: want %q, got %q", i, want[i], got)
+		}
+	}
+}
+
+func TestLattice_SetNormalizedSentence(t *testing.T) {
+	mecab, err := New(rcfile(map[string]string{}))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer mecab.Destroy()
+
+	lattice, err := NewLattice()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer lattice.Destroy()
+
+	input := "ＰＣで世界"
+	n := lattice.SetNormalizedSentence(input, DefaultNormalizer)
+	if err := mecab.ParseLattice(lattice); err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	tokens := n.Tokens(lattice.BOSNode().Tokens())
+	if len(tokens) == 0 || tokens[0].Surface != "PC" || input[tokens[0].Start:tokens[0].End] != "ＰＣ" {
+		t.Errorf("unexpected tokens: %v", tokens)
+	}
+}
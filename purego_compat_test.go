@@ -0,0 +1,145 @@
+//go:build cgo && !purego
+
+package mecab
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shogo82148/go-mecab/internal/viterbi"
+)
+
+// libmecab reports whether the tests run with libmecab.
+const libmecab = true
+
+// TestPureGo_compatible checks that the pure Go implementation, which is used with the purego tag,
+// writes the same output as libmecab.
+// The costs of the test dictionary are all zero, so it also checks that the ties are broken in the same way.
+func TestPureGo_compatible(t *testing.T) {
+	dir := buildTestDictionary(t)
+	rc := filepath.Join(dir, "mecabrc")
+	if err := os.WriteFile(rc, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open("testdata/corpus.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var sentences []string
+	err = readGoldCorpus("Test", f, func(tokens []Token) error {
+		sentences = append(sentences, joinSurfaces(tokens), strings.ReplaceAll(joinSurfaces(tokens), " ", ""))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sentences = append(sentences,
+		"",
+		"吾輩は猫である。",
+		"ABC abc  123 カタカナ",
+		"猫 ",
+		"  猫",
+		"ねこねこねこねこねこねこねこねこねこねこねこねこねこねこねこねこねこねこねこねこねこねこねこねこねこ",
+		"😀猫\xff",
+	)
+
+	formats := []map[string]string{
+		{},
+		{"output-format-type": "wakati"},
+		{
+			"node-format": `%m\t%M\t%pS|%pi,%ps,%pe,%pl,%pL,%pc,%pC,%pn,%pw,%phl,%phr,%h,%s,%t,%pb\t%f[0,1]\t%F-[2,3]\n`,
+			"unk-format":  `%m\t%H\t%c\n`,
+			"bos-format":  `BOS %S %L\n`,
+			"eos-format":  `EOS %pc\n`,
+		},
+	}
+	for _, format := range formats {
+		args := map[string]string{
+			"rcfile": rc,
+			"dicdir": dir,
+		}
+		for k, v := range format {
+			args[k] = v
+		}
+
+		tagger, err := New(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tagger.Destroy()
+		model, err := viterbi.Open(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer model.Close()
+
+		for _, s := range sentences {
+			want, err := tagger.Parse(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l := model.NewLattice()
+			l.SetSentence(s)
+			if !model.Parse(l) {
+				t.Fatal(l.What())
+			}
+			got, err := l.String()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("%v %q: want %q, got %q", format, s, want, got)
+			}
+		}
+	}
+
+	// N-best
+	tagger, err := New(map[string]string{"rcfile": rc, "dicdir": dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tagger.Destroy()
+	model, err := viterbi.Open(map[string]string{"rcfile": rc, "dicdir": dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer model.Close()
+	for _, s := range sentences {
+		lattice, err := NewLattice()
+		if err != nil {
+			t.Fatal(err)
+		}
+		lattice.SetSentence(s)
+		lattice.AddRequestType(RequestTypeNBest)
+		if err := tagger.ParseLattice(lattice); err != nil {
+			t.Fatal(err)
+		}
+		var want strings.Builder
+		for i := 0; i < 10 && lattice.Next(); i++ {
+			want.WriteString(lattice.String())
+		}
+		lattice.Destroy()
+
+		l := viterbi.NewLattice()
+		l.SetSentence(s)
+		l.SetRequestType(viterbi.OneBest | viterbi.NBest)
+		if !model.Parse(l) {
+			t.Fatal(l.What())
+		}
+		var got strings.Builder
+		for i := 0; i < 10 && l.Next(); i++ {
+			s, err := l.String()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got.WriteString(s)
+		}
+		if got.String() != want.String() {
+			t.Errorf("nbest %q: want %q, got %q", s, want.String(), got.String())
+		}
+	}
+}
@@ -0,0 +1,294 @@
+//go:build !cgo || purego
+
+package mecab
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shogo82148/go-mecab/internal/dictest"
+)
+
+// libmecab reports whether the tests run with libmecab.
+const libmecab = false
+
+func TestPureGo(t *testing.T) {
+	args := buildPureGoDictionary(t, "UTF-8")
+	tagger, err := New(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tagger.Destroy()
+
+	want := "東京\t名詞,固有名詞,トウキョウ\n都\t名詞,接尾,ト\nEOS\n"
+	got, err := tagger.Parse("東京都")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	node, err := tagger.ParseToNode("東京 都")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tokens []string
+	for node := range node.Morphs() {
+		tokens = append(tokens, node.Surface()+"/"+node.Feature())
+	}
+	if want := "東京/名詞,固有名詞,トウキョウ 都/名詞,接尾,ト"; strings.Join(tokens, " ") != want {
+		t.Errorf("want %q, got %q", want, tokens)
+	}
+
+	info := tagger.DictionaryInfo()
+	if len(info) != 1 || info[0].Type != SystemDictionary || info[0].Charset != "UTF-8" || info[0].Size != 3 {
+		t.Errorf("unexpected dictionary info: %#v", info)
+	}
+}
+
+func TestPureGo_nbest(t *testing.T) {
+	args := buildPureGoDictionary(t, "UTF-8")
+	model, err := NewModel(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer model.Destroy()
+	tagger, err := model.NewMeCab()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tagger.Destroy()
+	lattice, err := model.NewLattice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lattice.Destroy()
+
+	lattice.SetSentence("東京都")
+	lattice.AddRequestType(RequestTypeNBest)
+	if err := tagger.ParseLattice(lattice); err != nil {
+		t.Fatal(err)
+	}
+	var results []string
+	for lattice.Next() {
+		results = append(results, lattice.String())
+	}
+	want := []string{
+		"東京\t名詞,固有名詞,トウキョウ\n都\t名詞,接尾,ト\nEOS\n",
+		"東京都\t名詞,固有名詞,トウキョウト\nEOS\n",
+	}
+	if strings.Join(results, "") != strings.Join(want, "") {
+		t.Errorf("want %q, got %q", want, results)
+	}
+}
+
+func TestPureGo_swap(t *testing.T) {
+	args := buildPureGoDictionary(t, "UTF-8")
+	model, err := NewModel(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer model.Destroy()
+	tagger, err := model.NewMeCab()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tagger.Destroy()
+
+	userdic := filepath.Join(t.TempDir(), "user.dic")
+	user := []dictest.Entry{
+		{Surface: "東京都", LCAttr: 1, RCAttr: 1, WCost: 100, Feature: "名詞,固有名詞,トーキョート"},
+	}
+	if err := os.WriteFile(userdic, dictest.Dictionary(1, 2, 2, "UTF-8", user), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args["userdic"] = userdic
+	model2, err := NewModel(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer model2.Destroy()
+	if err := model.Swap(model2); err != nil {
+		t.Fatal(err)
+	}
+	if model.m.args["userdic"] != userdic {
+		t.Errorf("want the arguments of model2, got %v", model.m.args)
+	}
+
+	want := "東京都\t名詞,固有名詞,トーキョート\nEOS\n"
+	got, err := tagger.Parse("東京都")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestPureGo_error(t *testing.T) {
+	args := buildPureGoDictionary(t, "EUC-JP")
+	if _, err := New(args); !errors.Is(err, ErrCharsetMismatch) {
+		t.Errorf("want ErrCharsetMismatch, got %v", err)
+	}
+
+	args = buildPureGoDictionary(t, "UTF-8")
+	args["dicdir"] = filepath.Join(args["dicdir"], "missing")
+	if _, err := New(args); !errors.Is(err, ErrDictionaryNotFound) {
+		t.Errorf("want ErrDictionaryNotFound, got %v", err)
+	}
+
+	args = buildPureGoDictionary(t, "UTF-8")
+	args["marginal"] = ""
+	if _, err := New(args); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("want ErrInvalidOption, got %v", err)
+	}
+
+	err := CompileUserDictionary(t.TempDir(), "UTF-8", filepath.Join(t.TempDir(), "user.dic"), []UserEntry{
+		{Surface: "東京都", Features: []string{"名詞"}},
+	})
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestPureGo_search(t *testing.T) {
+	args := buildPureGoDictionary(t, "UTF-8")
+	model, err := NewModel(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer model.Destroy()
+
+	entries, err := model.CommonPrefixSearch("東京都庁")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Surface+"/"+entry.Feature)
+	}
+	if want := "東京/名詞,固有名詞,トウキョウ 東京都/名詞,固有名詞,トウキョウト"; strings.Join(got, " ") != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	entries, err = model.ExactMatch("都")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DictionaryEntry{
+		Surface:  "都",
+		Feature:  "名詞,接尾,ト",
+		Type:     SystemDictionary,
+		Filename: filepath.Join(args["dicdir"], "sys.dic"),
+		PosID:    2,
+		LCAttr:   1,
+		RCAttr:   1,
+		WCost:    2000,
+	}
+	if len(entries) != 1 || entries[0] != want {
+		t.Errorf("want %#v, got %#v", want, entries)
+	}
+}
+
+func TestPureGo_charCategories(t *testing.T) {
+	args := buildPureGoDictionary(t, "UTF-8")
+	model, err := NewModel(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer model.Destroy()
+
+	categories, err := model.CharCategories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CharCategory{
+		{ID: 0, Name: "DEFAULT", Group: true},
+		{ID: 1, Name: "SPACE", Group: true},
+		{ID: 2, Name: "KANJI", Length: 2},
+	}
+	if !slices.Equal(categories, want) {
+		t.Errorf("want %v, got %v", want, categories)
+	}
+	got, err := model.CategoryOf('語')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != want[2] {
+		t.Errorf("want %v, got %v", want[2], got)
+	}
+
+	tagger, err := model.NewMeCab()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tagger.Destroy()
+	lattice, err := model.NewLattice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lattice.Destroy()
+	lattice.SetSentence("漢字語")
+	if err := tagger.ParseLattice(lattice); err != nil {
+		t.Fatal(err)
+	}
+	for node := range lattice.BOSNode().Morphs() {
+		if name := node.CharTypeName(); name != "KANJI" {
+			t.Errorf("%s: want KANJI, got %q", node.Surface(), name)
+		}
+	}
+}
+
+func TestPureGo_idTables(t *testing.T) {
+	args := buildPureGoDictionary(t, "UTF-8")
+	err := dictest.WriteFiles(args["dicdir"], map[string][]byte{
+		"pos-id.def":   []byte("その他,間投,*,* 0\n名詞,固有名詞,*,* 1\n名詞,接尾,*,* 2\n"),
+		"left-id.def":  []byte("0 BOS/EOS,*,*\n1 名詞,*,*\n"),
+		"right-id.def": []byte("0 BOS/EOS,*,*\n1 名詞,*,*\n"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, err := NewModel(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer model.Destroy()
+
+	pos, err := model.PosIDTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := pos.ID("名詞,接尾,*,*"); !ok || id != 2 {
+		t.Errorf("want 2, got %d, %v", id, ok)
+	}
+	left, err := model.LeftIDTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := left.Name(1); name != "名詞,*,*" {
+		t.Errorf("want %q, got %q", "名詞,*,*", name)
+	}
+
+	tagger, err := model.NewMeCab()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tagger.Destroy()
+	node, err := tagger.ParseToNode("東京都")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for node := range node.Morphs() {
+		got = append(got, node.PosName())
+	}
+	if want := []string{"名詞,固有名詞,*,*", "名詞,接尾,*,*"}; !slices.Equal(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
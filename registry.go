@@ -0,0 +1,296 @@
+package mecab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownModel is returned by [Registry] when the model is not registered.
+var ErrUnknownModel = errors.New("mecab: unknown model")
+
+var errRegistryClosed = errors.New("mecab: registry is closed")
+
+// DefaultMaxIdleTaggers is the default value of [Registry.MaxIdleTaggers].
+const DefaultMaxIdleTaggers = 4
+
+// Registry holds named models, such as "ipadic", "unidic" and "neologd".
+// The models are loaded lazily on first use, and each model has its own tagger pool.
+// The models are reference counted, and [Registry.Unload] destroys them when they are no longer used.
+// Registry is safe for concurrent use by multiple goroutines.
+type Registry struct {
+	// MaxIdleTaggers is the maximum number of idle taggers for each model.
+	// If it is zero, DefaultMaxIdleTaggers is used.
+	MaxIdleTaggers int
+
+	mu      sync.Mutex
+	entries map[string]*registryEntry
+	closed  bool
+}
+
+type registryEntry struct {
+	name string
+	load func() (Model, error)
+
+	// refs and unload are guarded by Registry.mu.
+	refs   int
+	unload bool
+
+	// loadMu guards model.
+	loadMu sync.Mutex
+	model  Model
+
+	// poolMu guards taggers.
+	poolMu  sync.Mutex
+	taggers []MeCab
+}
+
+// NewRegistry returns a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: make(map[string]*registryEntry),
+	}
+}
+
+// Register registers the model that is created by [NewModel] with args.
+func (r *Registry) Register(name string, args map[string]string) error {
+	copied := make(map[string]string, len(args))
+	for k, v := range args {
+		copied[k] = v
+	}
+	return r.RegisterFunc(name, func() (Model, error) {
+		return NewModel(copied)
+	})
+}
+
+// RegisterFunc registers the model that is created by load.
+// load is called on first use of the model, and after the model is unloaded.
+func (r *Registry) RegisterFunc(name string, load func() (Model, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errRegistryClosed
+	}
+	if _, ok := r.entries[name]; ok {
+		return fmt.Errorf("mecab: model %q is already registered", name)
+	}
+	r.entries[name] = &registryEntry{
+		name: name,
+		load: load,
+	}
+	return nil
+}
+
+// Names returns the names of the registered models in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Loaded reports whether the model is loaded.
+func (r *Registry) Loaded(name string) bool {
+	r.mu.Lock()
+	e, ok := r.entries[name]
+	r.mu.Unlock()
+	if !ok {
+		return false
+	}
+	e.loadMu.Lock()
+	defer e.loadMu.Unlock()
+	return e.model.m != nil
+}
+
+// Parse parses the text with the model named name, and returns the result as string.
+func (r *Registry) Parse(ctx context.Context, name, text string) (string, error) {
+	var result string
+	err := r.Do(ctx, name, func(m MeCab) error {
+		var err error
+		result, err = m.Parse(text)
+		return err
+	})
+	return result, err
+}
+
+// Do calls f with a tagger of the model named name.
+// The tagger is returned to the pool after f returns,
+// so f must not use the tagger and the nodes that it returns after f returns.
+func (r *Registry) Do(ctx context.Context, name string, f func(m MeCab) error) error {
+	e, err := r.acquire(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer r.release(e)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m, err := e.getTagger()
+	if err != nil {
+		return err
+	}
+	defer e.putTagger(m, r.maxIdleTaggers())
+	return f(m)
+}
+
+// Acquire returns the model named name, and increments the reference count.
+// The caller must call the returned release function after using the model.
+func (r *Registry) Acquire(ctx context.Context, name string) (Model, func(), error) {
+	e, err := r.acquire(ctx, name)
+	if err != nil {
+		return Model{}, nil, err
+	}
+	var once sync.Once
+	return e.model, func() {
+		once.Do(func() { r.release(e) })
+	}, nil
+}
+
+// Unload destroys the model named name when it is no longer used.
+// If the model is in use, it is destroyed after the last user releases it.
+// The model is loaded again on next use.
+func (r *Registry) Unload(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownModel, name)
+	}
+	if e.refs == 0 {
+		e.destroy()
+		return nil
+	}
+	e.unload = true
+	return nil
+}
+
+// UnloadUnused destroys all models that are not in use.
+func (r *Registry) UnloadUnused() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.entries {
+		if e.refs == 0 {
+			e.destroy()
+		}
+	}
+}
+
+// Close unloads all models. The models in use are destroyed after they are released.
+// The registry is not available after Close.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	for _, e := range r.entries {
+		if e.refs == 0 {
+			e.destroy()
+		} else {
+			e.unload = true
+		}
+	}
+	return nil
+}
+
+func (r *Registry) maxIdleTaggers() int {
+	if r.MaxIdleTaggers > 0 {
+		return r.MaxIdleTaggers
+	}
+	return DefaultMaxIdleTaggers
+}
+
+func (r *Registry) acquire(ctx context.Context, name string) (*registryEntry, error) {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil, errRegistryClosed
+	}
+	e, ok := r.entries[name]
+	if !ok {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w: %q", ErrUnknownModel, name)
+	}
+	e.refs++
+	r.mu.Unlock()
+
+	if err := e.ensureLoaded(ctx); err != nil {
+		r.release(e)
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *Registry) release(e *registryEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.refs--
+	if e.refs == 0 && e.unload {
+		e.destroy()
+	}
+}
+
+func (e *registryEntry) ensureLoaded(ctx context.Context) error {
+	e.loadMu.Lock()
+	defer e.loadMu.Unlock()
+	if e.model.m != nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m, err := e.load()
+	if err != nil {
+		return err
+	}
+	e.model = m
+	return nil
+}
+
+func (e *registryEntry) getTagger() (MeCab, error) {
+	e.poolMu.Lock()
+	if n := len(e.taggers); n > 0 {
+		m := e.taggers[n-1]
+		e.taggers = e.taggers[:n-1]
+		e.poolMu.Unlock()
+		return m, nil
+	}
+	e.poolMu.Unlock()
+	return e.model.NewMeCab()
+}
+
+func (e *registryEntry) putTagger(m MeCab, maxIdle int) {
+	e.poolMu.Lock()
+	if len(e.taggers) < maxIdle {
+		e.taggers = append(e.taggers, m)
+		e.poolMu.Unlock()
+		return
+	}
+	e.poolMu.Unlock()
+	m.Destroy()
+}
+
+// destroy destroys the taggers and the model.
+// The caller must hold Registry.mu, and the reference count must be zero.
+func (e *registryEntry) destroy() {
+	e.unload = false
+
+	e.poolMu.Lock()
+	for _, m := range e.taggers {
+		m.Destroy()
+	}
+	e.taggers = nil
+	e.poolMu.Unlock()
+
+	e.loadMu.Lock()
+	if e.model.m != nil {
+		e.model.Destroy()
+	}
+	e.model = Model{}
+	e.loadMu.Unlock()
+}
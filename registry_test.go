@@ -0,0 +1,155 @@
+package mecab
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+	defer r.Close()
+
+	if err := r.Register("ipadic", rcfile(map[string]string{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("unidic", rcfile(map[string]string{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("ipadic", rcfile(map[string]string{})); err == nil {
+		t.Error("want error, but not")
+	}
+	if got, want := r.Names(), []string{"ipadic", "unidic"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// the models are loaded lazily.
+	if r.Loaded("ipadic") {
+		t.Error("want not loaded, but loaded")
+	}
+}
+
+func TestRegistry_unknown(t *testing.T) {
+	r := NewRegistry()
+	defer r.Close()
+
+	_, err := r.Parse(context.Background(), "unknown", "こんにちは世界")
+	if !errors.Is(err, ErrUnknownModel) {
+		t.Errorf("want ErrUnknownModel, got %v", err)
+	}
+}
+
+func TestRegistry_loadError(t *testing.T) {
+	r := NewRegistry()
+	defer r.Close()
+
+	errLoad := errors.New("load error")
+	var calls int
+	err := r.RegisterFunc("broken", func() (Model, error) {
+		calls++
+		return Model{}, errLoad
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := r.Parse(context.Background(), "broken", "こんにちは世界")
+		if !errors.Is(err, errLoad) {
+			t.Errorf("want errLoad, got %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("want 2 calls, got %d", calls)
+	}
+	if r.Loaded("broken") {
+		t.Error("want not loaded, but loaded")
+	}
+}
+
+func TestRegistry_canceled(t *testing.T) {
+	r := NewRegistry()
+	defer r.Close()
+
+	err := r.RegisterFunc("ipadic", func() (Model, error) {
+		t.Error("the model must not be loaded")
+		return Model{}, errors.New("unexpected")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.Parse(ctx, "ipadic", "こんにちは世界"); !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+}
+
+func TestRegistry_Parse(t *testing.T) {
+	r := NewRegistry()
+	defer r.Close()
+
+	err := r.Register("ipadic", rcfile(map[string]string{
+		"output-format-type": "wakati",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := r.Parse(context.Background(), "ipadic", "こんにちは世界")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if want := "こんにちは 世界 \n"; result != want {
+				t.Errorf("want %q, got %q", want, result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !r.Loaded("ipadic") {
+		t.Error("want loaded, but not")
+	}
+	if err := r.Unload("ipadic"); err != nil {
+		t.Fatal(err)
+	}
+	if r.Loaded("ipadic") {
+		t.Error("want unloaded, but loaded")
+	}
+}
+
+func TestRegistry_Unload_inUse(t *testing.T) {
+	r := NewRegistry()
+	defer r.Close()
+
+	if err := r.Register("ipadic", rcfile(map[string]string{})); err != nil {
+		t.Fatal(err)
+	}
+
+	_, release, err := r.Acquire(context.Background(), "ipadic")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	if err := r.Unload("ipadic"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the model is in use, so it is not destroyed yet.
+	if !r.Loaded("ipadic") {
+		t.Error("want loaded, but not")
+	}
+	release()
+	if r.Loaded("ipadic") {
+		t.Error("want unloaded, but loaded")
+	}
+}
@@ -0,0 +1,202 @@
+package mecab
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/shogo82148/go-mecab/dic"
+)
+
+// DictionaryEntry is an entry of the system or user dictionaries.
+type DictionaryEntry struct {
+	// Surface is the surface string of the entry.
+	Surface string
+
+	// Feature is the feature string of the entry.
+	Feature string
+
+	// Type is the type of the dictionary that contains the entry.
+	Type DictionaryType
+
+	// Filename is the filename of the dictionary that contains the entry.
+	Filename string
+
+	// PosID is the part-of-speech ID. See [Model.PosIDTable].
+	PosID int
+
+	// LCAttr is the left context ID. See [Model.LeftIDTable].
+	LCAttr int
+
+	// RCAttr is the right context ID. See [Model.RightIDTable].
+	RCAttr int
+
+	// WCost is the cost of the word. A lower cost makes the word more likely to be chosen.
+	WCost int
+}
+
+// CommonPrefixSearch returns the entries of the system and user dictionaries
+// whose surfaces are prefixes of text.
+// The entries are sorted by the length of the surface, from the shortest.
+// The entries of the same surface are in the order of [Model.DictionaryInfo].
+//
+// The dictionary files are opened on the first call, and closed when the model is destroyed.
+func (m Model) CommonPrefixSearch(text string) ([]DictionaryEntry, error) {
+	if m.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+	return m.m.files.lookup("CommonPrefixSearch", m.DictionaryInfo(), text, false)
+}
+
+// ExactMatch returns the entries of the system and user dictionaries whose surface is surface.
+// The entries are in the order of [Model.DictionaryInfo].
+func (m Model) ExactMatch(surface string) ([]DictionaryEntry, error) {
+	if m.m.model == nil {
+		panic(errModelNotAvailable)
+	}
+	return m.m.files.lookup("ExactMatch", m.DictionaryInfo(), surface, true)
+}
+
+// dictionaryFiles holds the dictionary files opened by the methods of [Model].
+type dictionaryFiles struct {
+	mu    sync.Mutex
+	names []string
+	dicts []*dic.Dictionary
+	codec *codec
+
+	// char and categories are loaded by the methods of the character categories.
+	dicdir     string
+	char       *dic.CharProperty
+	categories []CharCategory
+
+	// tables are the ID tables of the files in tableDir, e.g. pos-id.def.
+	tableDir string
+	tables   map[string]*IDTable
+}
+
+func (c *dictionaryFiles) lookup(op string, info []DictionaryInfo, key string, exact bool) ([]DictionaryEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.open(op, info); err != nil {
+		return nil, err
+	}
+	if c.codec != nil {
+		var err error
+		key, err = c.codec.encode(op, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var ret []DictionaryEntry
+	var lengths []int
+	for i, d := range c.dicts {
+		if exact {
+			for _, t := range d.ExactMatch(key) {
+				ret = append(ret, c.newEntry(i, key, t))
+			}
+			continue
+		}
+		for _, match := range d.CommonPrefixSearch(key) {
+			for _, t := range match.Tokens {
+				ret = append(ret, c.newEntry(i, key[:match.Length], t))
+				lengths = append(lengths, match.Length)
+			}
+		}
+	}
+	if !exact {
+		// sort by the length in the charset of the dictionary,
+		// because the lengths in UTF-8 are in the same order.
+		idx := make([]int, len(ret))
+		for i := range idx {
+			idx[i] = i
+		}
+		slices.SortStableFunc(idx, func(a, b int) int {
+			return cmp.Compare(lengths[a], lengths[b])
+		})
+		sorted := make([]DictionaryEntry, len(ret))
+		for i, j := range idx {
+			sorted[i] = ret[j]
+		}
+		ret = sorted
+	}
+	return ret, nil
+}
+
+// open opens the system and user dictionaries in info.
+// They are reopened if the model is swapped.
+func (c *dictionaryFiles) open(op string, info []DictionaryInfo) error {
+	var names []string
+	for _, i := range info {
+		if i.Type != UnknownDictionary {
+			names = append(names, i.Filename)
+		}
+	}
+	if c.dicts != nil && slices.Equal(c.names, names) {
+		return nil
+	}
+	c.closeDictionaries()
+
+	var cd *codec
+	if len(info) > 0 {
+		var err error
+		cd, err = newCodec(op, info[0].Charset)
+		if err != nil {
+			return err
+		}
+	}
+	dicts := make([]*dic.Dictionary, 0, len(names))
+	for _, name := range names {
+		d, err := dic.Open(name)
+		if err != nil {
+			for _, d := range dicts {
+				d.Close()
+			}
+			return &Error{
+				Op:   op,
+				Path: name,
+				Kind: KindDictionaryNotFound,
+				err:  err.Error(),
+			}
+		}
+		dicts = append(dicts, d)
+	}
+	c.names, c.dicts, c.codec = names, dicts, cd
+	return nil
+}
+
+func (c *dictionaryFiles) newEntry(i int, surface string, t dic.Token) DictionaryEntry {
+	d := c.dicts[i]
+	feature := d.Feature(t)
+	if c.codec != nil {
+		surface = c.codec.decode(surface)
+		feature = c.codec.decode(feature)
+	}
+	return DictionaryEntry{
+		Surface:  surface,
+		Feature:  feature,
+		Type:     DictionaryType(d.Type),
+		Filename: c.names[i],
+		PosID:    int(t.PosID),
+		LCAttr:   int(t.LCAttr),
+		RCAttr:   int(t.RCAttr),
+		WCost:    int(t.WCost),
+	}
+}
+
+// close closes the dictionaries and the character definitions.
+func (c *dictionaryFiles) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeDictionaries()
+	c.closeCharProperty()
+	c.tableDir, c.tables = "", nil
+}
+
+func (c *dictionaryFiles) closeDictionaries() {
+	for _, d := range c.dicts {
+		d.Close()
+	}
+	c.names, c.dicts, c.codec = nil, nil, nil
+}
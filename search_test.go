@@ -0,0 +1,65 @@
+package mecab
+
+import (
+	"testing"
+)
+
+func TestModel_CommonPrefixSearch(t *testing.T) {
+	model, err := NewModel(rcfile(map[string]string{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer model.Destroy()
+
+	entries, err := model.CommonPrefixSearch("東京都庁")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("want some entries, got none")
+	}
+	surfaces := map[string]bool{}
+	for i, entry := range entries {
+		surfaces[entry.Surface] = true
+		if i > 0 && len(entries[i-1].Surface) > len(entry.Surface) {
+			t.Errorf("entries are not sorted by length: %q before %q", entries[i-1].Surface, entry.Surface)
+		}
+		if entry.Type != SystemDictionary || entry.Feature == "" || entry.Filename == "" {
+			t.Errorf("unexpected entry: %#v", entry)
+		}
+	}
+	for _, want := range []string{"東", "東京", "東京都"} {
+		if !surfaces[want] {
+			t.Errorf("want %q in %v", want, surfaces)
+		}
+	}
+}
+
+func TestModel_ExactMatch(t *testing.T) {
+	model, err := NewModel(rcfile(map[string]string{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer model.Destroy()
+
+	entries, err := model.ExactMatch("世界")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("want some entries, got none")
+	}
+	for _, entry := range entries {
+		if entry.Surface != "世界" {
+			t.Errorf("want %q, got %q", "世界", entry.Surface)
+		}
+	}
+
+	entries, err = model.ExactMatch("世界世界世界")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("want no entries, got %v", entries)
+	}
+}
@@ -0,0 +1,79 @@
+package mecab
+
+// Token is a morph copied from a [Node].
+// Unlike Node, it is valid after the owner parses a new sentence or is destroyed.
+type Token struct {
+	// Surface is the surface string.
+	Surface string `json:"surface"`
+
+	// Feature is the feature string.
+	Feature string `json:"feature"`
+
+	// Start and End are the byte offsets of the surface in the input.
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	// Stat is the type of the node.
+	Stat NodeStat `json:"stat"`
+
+	// PosID is the part-of-speech ID. See [Model.PosIDTable].
+	PosID int `json:"pos_id"`
+
+	// LCAttr is the left context ID. See [Model.LeftIDTable].
+	LCAttr int `json:"lc_attr"`
+
+	// RCAttr is the right context ID. See [Model.RightIDTable].
+	RCAttr int `json:"rc_attr"`
+
+	// CharType is the character category of the first character.
+	// See [Model.CharCategories].
+	CharType int `json:"char_type"`
+
+	// WCost is the cost of the word. A lower cost makes the word more likely to be chosen.
+	WCost int `json:"wcost"`
+
+	// Cost is the best accumulative cost from the BOS node to this node.
+	Cost int `json:"cost"`
+}
+
+// Token returns the token of the node.
+// The offsets are relative to the beginning of the node.
+func (node Node) Token() Token {
+	return Token{
+		Surface:  node.Surface(),
+		Feature:  node.Feature(),
+		Start:    0,
+		End:      node.Length(),
+		Stat:     node.Stat(),
+		PosID:    node.PosID(),
+		LCAttr:   node.LCAttr(),
+		RCAttr:   node.RCAttr(),
+		CharType: node.CharType(),
+		WCost:    node.WCost(),
+		Cost:     node.Cost(),
+	}
+}
+
+// Tokens returns the tokens of the node and the following nodes, excluding BOS and EOS nodes.
+// node must be the BOS node, e.g. the result of [MeCab.ParseToNode] or [Lattice.BOSNode].
+func (node Node) Tokens() []Token {
+	return node.tokens(0)
+}
+
+// tokens is same as Tokens, but base is added to the offsets.
+func (node Node) tokens(base int) []Token {
+	var tokens []Token
+	pos := base
+	for n := range node.Nodes() {
+		if isBoundary(n) {
+			continue
+		}
+		// RLength includes the white spaces before the morph.
+		pos += n.RLength()
+		t := n.Token()
+		t.Start = pos - n.Length()
+		t.End = pos
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
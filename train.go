@@ -0,0 +1,288 @@
+package mecab
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TrainProgress is the progress of the training, reported at the end of each iteration.
+type TrainProgress struct {
+	// Iteration is the number of the iteration, starting at 0.
+	Iteration int
+
+	// ErrorRate is the ratio of the wrong tokens.
+	ErrorRate float64
+
+	// F is the F-measure on the training corpus.
+	F float64
+
+	// Target is the value of the objective function.
+	Target float64
+
+	// Diff is the relative difference of the objective function from the previous iteration.
+	// The training stops when it is smaller than [Trainer.Eta].
+	Diff float64
+}
+
+// Trainer trains the CRF model of a dictionary, as mecab-cost-train does.
+//
+// The corpus is in the format of the output of MeCab: each line has a surface and the features
+// separated by a tab, and each sentence ends with "EOS".
+// It must be encoded in the charset of the seed dictionary.
+//
+// Without Progress, the model is trained by libmecab in the process,
+// and libmecab writes the progress to the standard output as mecab-cost-train does.
+type Trainer struct {
+	// Dicdir is the directory of the seed dictionary.
+	// It must be compiled by mecab-dict-index, and contain feature.def and rewrite.def.
+	Dicdir string
+
+	// C is the hyper parameter of the regularization. If it is zero, 1.0 is used.
+	// A larger C fits the corpus more closely.
+	C float64
+
+	// Threads is the number of the threads. If it is zero, 1 is used.
+	Threads int
+
+	// Eta is the tolerance of the termination criterion. If it is zero, 0.00005 is used.
+	// libmecab has no limit of the iterations, so Eta controls when the training stops.
+	Eta float64
+
+	// Freq is the frequency cut-off of the features. If it is zero, 1 is used.
+	Freq int
+
+	// OldModel is the model file to start the training from, if it is not empty.
+	OldModel string
+
+	// Progress is called at the end of each iteration if it is not nil.
+	// The progress is read from the standard output of mecab-cost-train,
+	// so the command runs in a subprocess, and it must be installed in PATH
+	// or in the libexec directory of "mecab-config --libexecdir".
+	Progress func(TrainProgress)
+}
+
+// Train trains the model with the corpus files, and writes it to the file model.
+func (t *Trainer) Train(model string, corpus ...string) error {
+	const op = "Train"
+
+	if len(corpus) == 0 {
+		return &Error{
+			Op:   op,
+			Kind: KindInvalidInput,
+			err:  "no corpus",
+		}
+	}
+	readers := make([]io.Reader, 0, len(corpus))
+	for _, name := range corpus {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		readers = append(readers, f)
+	}
+	return t.train(op, model, readers, corpus)
+}
+
+// TrainReader trains the model with the corpus read from r, and writes it to the file model.
+func (t *Trainer) TrainReader(model string, r io.Reader) error {
+	return t.train("Train", model, []io.Reader{r}, []string{"corpus"})
+}
+
+func (t *Trainer) train(op, model string, readers []io.Reader, names []string) error {
+	// MeCab terminates the process if the corpus is broken, so the corpus is validated here.
+	f, err := os.CreateTemp("", "mecab-corpus-*.txt")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	w := bufio.NewWriter(f)
+	sentences := 0
+	for i, r := range readers {
+		n, err := copyCorpus(op, names[i], w, r)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		sentences += n
+	}
+	err = w.Flush()
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+	if sentences == 0 {
+		return &Error{
+			Op:   op,
+			Kind: KindInvalidInput,
+			err:  "no sentences in the corpus",
+		}
+	}
+
+	for _, name := range []string{"sys.dic", "feature.def", "rewrite.def"} {
+		if _, err := os.Stat(filepath.Join(t.Dicdir, name)); err != nil {
+			return &Error{
+				Op:   op,
+				Path: t.Dicdir,
+				Kind: KindDictionaryNotFound,
+				err:  "no such file: " + name,
+			}
+		}
+	}
+
+	args := []string{
+		"mecab-cost-train",
+		"--dicdir=" + t.Dicdir,
+		"--cost=" + strconv.FormatFloat(orDefault(t.C, 1.0), 'g', -1, 64),
+		"--thread=" + strconv.Itoa(orDefault(t.Threads, 1)),
+		"--eta=" + strconv.FormatFloat(orDefault(t.Eta, 0.00005), 'g', -1, 64),
+		"--freq=" + strconv.Itoa(orDefault(t.Freq, 1)),
+	}
+	if t.OldModel != "" {
+		args = append(args, "--old-model="+t.OldModel)
+	}
+	args = append(args, f.Name(), model)
+
+	return t.costTrain(model, args)
+}
+
+// runCostTrain runs mecab-cost-train in a subprocess with args, and reports the progress.
+func (t *Trainer) runCostTrain(model string, args []string) error {
+	const op = "Train"
+
+	path, err := lookMeCabTool("mecab-cost-train")
+	if err != nil {
+		return &Error{
+			Op:   op,
+			Path: model,
+			err:  "reporting the progress requires mecab-cost-train: " + err.Error(),
+		}
+	}
+	cmd := exec.Command(path, args[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	s := bufio.NewScanner(stdout)
+	for s.Scan() {
+		if p, ok := parseTrainProgress(s.Text()); ok {
+			t.Progress(p)
+		}
+	}
+	io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		msg := "mecab-cost-train failed: " + err.Error()
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			msg += ": " + detail
+		}
+		return &Error{
+			Op:   op,
+			Path: model,
+			err:  msg,
+		}
+	}
+	return nil
+}
+
+// parseTrainProgress parses a line of the progress, e.g.
+// "iter=0 err=0.5 F=0.6 target=123.4 diff=1".
+func parseTrainProgress(line string) (TrainProgress, bool) {
+	if !strings.HasPrefix(line, "iter=") {
+		return TrainProgress{}, false
+	}
+	var p TrainProgress
+	for _, field := range strings.Fields(line) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		var err error
+		switch key {
+		case "iter":
+			p.Iteration, err = strconv.Atoi(value)
+		case "err":
+			p.ErrorRate, err = strconv.ParseFloat(value, 64)
+		case "F":
+			p.F, err = strconv.ParseFloat(value, 64)
+		case "target":
+			p.Target, err = strconv.ParseFloat(value, 64)
+		case "diff":
+			p.Diff, err = strconv.ParseFloat(value, 64)
+		}
+		if err != nil {
+			return TrainProgress{}, false
+		}
+	}
+	return p, true
+}
+
+// copyCorpus validates the corpus read from r, and copies it to w.
+// It returns the number of the sentences.
+func copyCorpus(op, name string, w io.Writer, r io.Reader) (int, error) {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	inSentence := false
+	n, sentences := 0, 0
+	for s.Scan() {
+		n++
+		line := strings.TrimSuffix(s.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if line == "EOS" {
+			if !inSentence {
+				// skip empty sentences
+				continue
+			}
+			sentences++
+			inSentence = false
+		} else {
+			surface, feature, ok := strings.Cut(line, "\t")
+			if !ok || surface == "" || feature == "" {
+				return 0, corpusError(op, name, n, "want surface and feature separated by a tab")
+			}
+			inSentence = true
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return 0, err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return 0, err
+	}
+	if inSentence {
+		return 0, corpusError(op, name, n, "the last sentence doesn't end with EOS")
+	}
+	return sentences, nil
+}
+
+func corpusError(op, name string, line int, msg string) error {
+	return &Error{
+		Op:   op,
+		Path: name,
+		Kind: KindInvalidInput,
+		err:  fmt.Sprintf("%s:%d: %s", name, line, msg),
+	}
+}
+
+func orDefault[T comparable](v, def T) T {
+	var zero T
+	if v == zero {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,43 @@
+//go:build cgo && !purego
+
+package mecab
+
+// #include <mecab.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// trainMu serializes mecab_cost_train, because it is not designed to be called concurrently.
+var trainMu sync.Mutex
+
+// costTrain calls mecab_cost_train with args.
+// If the progress is reported, mecab-cost-train runs in a subprocess instead,
+// because libmecab writes the progress to the standard output of the process.
+func (t *Trainer) costTrain(model string, args []string) error {
+	if t.Progress != nil {
+		return t.runCostTrain(model, args)
+	}
+
+	cargs := make([]*C.char, len(args))
+	for i, arg := range args {
+		cargs[i] = C.CString(arg)
+		defer C.free(unsafe.Pointer(cargs[i]))
+	}
+
+	trainMu.Lock()
+	defer trainMu.Unlock()
+
+	ret := C.mecab_cost_train(C.int(len(cargs)), (**C.char)(&cargs[0]))
+	if ret != 0 {
+		return &Error{
+			Op:   "Train",
+			Path: model,
+			err:  "mecab-cost-train failed",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !cgo || purego
+
+package mecab
+
+// costTrain is not available without libmecab.
+func (t *Trainer) costTrain(model string, args []string) error {
+	return &Error{
+		Op:   "Train",
+		Path: model,
+		err:  "training requires libmecab, but it is built without cgo",
+	}
+}
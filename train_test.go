@@ -0,0 +1,102 @@
+package mecab
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTrainProgress(t *testing.T) {
+	p, ok := parseTrainProgress("iter=3 err=0.25 F=0.8 target=123.5 diff=0.001")
+	if !ok {
+		t.Fatal("want ok")
+	}
+	want := TrainProgress{Iteration: 3, ErrorRate: 0.25, F: 0.8, Target: 123.5, Diff: 0.001}
+	if p != want {
+		t.Errorf("want %#v, got %#v", want, p)
+	}
+
+	if _, ok := parseTrainProgress("reading corpus ..."); ok {
+		t.Error("want not ok")
+	}
+}
+
+func TestCopyCorpus(t *testing.T) {
+	tests := []struct {
+		corpus    string
+		sentences int
+		err       string
+	}{
+		{"猫\t名詞\nEOS\n犬\t名詞\nEOS\n", 2, ""},
+		{"EOS\n猫\t名詞\r\nEOS\r\n", 1, ""},
+		{"猫\t名詞\n犬 名詞\nEOS\n", 0, "corpus:2:"},
+		{"猫\t名詞\n", 0, "doesn't end with EOS"},
+	}
+	for _, tt := range tests {
+		n, err := copyCorpus("Train", "corpus", io.Discard, strings.NewReader(tt.corpus))
+		if tt.err == "" {
+			if err != nil || n != tt.sentences {
+				t.Errorf("%q: want %d, got %d, %v", tt.corpus, tt.sentences, n, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) || !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%q: want error %q, got %v", tt.corpus, tt.err, err)
+		}
+	}
+}
+
+func TestTrainer_noDictionary(t *testing.T) {
+	tr := &Trainer{Dicdir: t.TempDir()}
+	err := tr.Train(filepath.Join(t.TempDir(), "model"), "testdata/corpus.txt")
+	if !errors.Is(err, ErrDictionaryNotFound) {
+		t.Errorf("want ErrDictionaryNotFound, got %v", err)
+	}
+}
+
+func TestTrainer(t *testing.T) {
+	dicdir := buildTestDictionary(t)
+	model := filepath.Join(t.TempDir(), "model")
+
+	tr := &Trainer{
+		Dicdir: dicdir,
+		C:      1.0,
+		Eta:    0.001,
+	}
+	if err := tr.Train(model, "testdata/corpus.txt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(model); err != nil {
+		t.Errorf("want model, got %v", err)
+	}
+}
+
+func TestTrainer_progress(t *testing.T) {
+	dicdir := buildTestDictionary(t)
+	if _, err := lookMeCabTool("mecab-cost-train"); err != nil {
+		t.Skipf("mecab-cost-train is not found: %v", err)
+	}
+	model := filepath.Join(t.TempDir(), "model")
+
+	var progress []TrainProgress
+	tr := &Trainer{
+		Dicdir: dicdir,
+		C:      1.0,
+		Eta:    0.001,
+		Progress: func(p TrainProgress) {
+			progress = append(progress, p)
+		},
+	}
+	if err := tr.Train(model, "testdata/corpus.txt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(model); err != nil {
+		t.Errorf("want model, got %v", err)
+	}
+	if len(progress) == 0 {
+		t.Error("want progress, got nothing")
+	}
+}
@@ -0,0 +1,233 @@
+package mecab
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+// UniDicLayout is a layout of the feature fields of UniDic.
+// The layout differs between UniDic versions.
+type UniDicLayout int
+
+const (
+	// UniDicLayoutAuto detects the layout from the number of the fields.
+	UniDicLayoutAuto UniDicLayout = iota
+
+	// UniDicLayoutCompact is the 17 fields layout,
+	// which has the fields from pos1 to fForm.
+	UniDicLayoutCompact
+
+	// UniDicLayoutV21 is the 26 fields layout of unidic-mecab 2.1.2.
+	// The fields after fForm are kana, kanaBase, form, formBase, iConType, fConType, aType, aConType and aModType.
+	UniDicLayoutV21
+
+	// UniDicLayoutV22 is the 29 fields layout of unidic-cwj and unidic-csj 2.2.0 and later.
+	// The fields after fForm are iConType, fConType, type, kana, kanaBase, form, formBase,
+	// aType, aConType, aModType, lid and lemma_id.
+	UniDicLayoutV22
+)
+
+func (layout UniDicLayout) String() string {
+	switch layout {
+	case UniDicLayoutAuto:
+		return "Auto"
+	case UniDicLayoutCompact:
+		return "Compact"
+	case UniDicLayoutV21:
+		return "V21"
+	case UniDicLayoutV22:
+		return "V22"
+	}
+	return ""
+}
+
+// UniDicFeature is a feature of UniDic.
+// The placeholder "*" is decoded as an empty string,
+// and the fields that the layout doesn't have are empty.
+type UniDicFeature struct {
+	// Layout is the layout of the feature.
+	Layout UniDicLayout
+
+	// POS1, POS2, POS3 and POS4 are the part-of-speech and its sub categories.
+	POS1 string
+	POS2 string
+	POS3 string
+	POS4 string
+
+	// CType is the conjugation type, and CForm is the conjugation form.
+	CType string
+	CForm string
+
+	// LForm is the reading of the lemma, and Lemma is the lemma.
+	LForm string
+	Lemma string
+
+	// Orth is the orthography, and Pron is the pronunciation.
+	Orth string
+	Pron string
+
+	// OrthBase and PronBase are the orthography and the pronunciation of the base form.
+	OrthBase string
+	PronBase string
+
+	// Goshu is the word origin, e.g. "和", "漢" and "外".
+	Goshu string
+
+	// IType and IForm are the type and the form of the initial sound change.
+	IType string
+	IForm string
+
+	// FType and FForm are the type and the form of the final sound change.
+	FType string
+	FForm string
+
+	// IConType and FConType are the initial and final connection types.
+	IConType string
+	FConType string
+
+	// Type is the type of the lemma. It is only available in UniDicLayoutV22.
+	Type string
+
+	// Kana and KanaBase are the reading in katakana of the word and the base form.
+	Kana     string
+	KanaBase string
+
+	// Form and FormBase are the word form of the word and the base form.
+	Form     string
+	FormBase string
+
+	// AType, AConType and AModType are the accent type, the accent connection type
+	// and the accent modification type.
+	AType    string
+	AConType string
+	AModType string
+
+	// LID and LemmaID are the IDs of the lexeme and the lemma. They are only available in UniDicLayoutV22.
+	LID     string
+	LemmaID string
+}
+
+// ParseUniDicFeature parses the feature string of UniDic.
+// The layout is detected from the number of the fields.
+func ParseUniDicFeature(feature string) UniDicFeature {
+	return ParseUniDicFeatureLayout(feature, UniDicLayoutAuto)
+}
+
+// ParseUniDicFeatureLayout parses the feature string of UniDic with the layout.
+// Missing fields, e.g. the fields of unknown words, are decoded as empty strings.
+func ParseUniDicFeatureLayout(feature string, layout UniDicLayout) UniDicFeature {
+	fields := SplitFeature(feature)
+	if layout == UniDicLayoutAuto {
+		layout = detectUniDicLayout(len(fields))
+	}
+
+	f := UniDicFeature{
+		Layout:   layout,
+		POS1:     featureField(fields, 0),
+		POS2:     featureField(fields, 1),
+		POS3:     featureField(fields, 2),
+		POS4:     featureField(fields, 3),
+		CType:    featureField(fields, 4),
+		CForm:    featureField(fields, 5),
+		LForm:    featureField(fields, 6),
+		Lemma:    featureField(fields, 7),
+		Orth:     featureField(fields, 8),
+		Pron:     featureField(fields, 9),
+		OrthBase: featureField(fields, 10),
+		PronBase: featureField(fields, 11),
+		Goshu:    featureField(fields, 12),
+		IType:    featureField(fields, 13),
+		IForm:    featureField(fields, 14),
+		FType:    featureField(fields, 15),
+		FForm:    featureField(fields, 16),
+	}
+
+	switch layout {
+	case UniDicLayoutV21:
+		f.Kana = featureField(fields, 17)
+		f.KanaBase = featureField(fields, 18)
+		f.Form = featureField(fields, 19)
+		f.FormBase = featureField(fields, 20)
+		f.IConType = featureField(fields, 21)
+		f.FConType = featureField(fields, 22)
+		f.AType = featureField(fields, 23)
+		f.AConType = featureField(fields, 24)
+		f.AModType = featureField(fields, 25)
+	case UniDicLayoutV22:
+		f.IConType = featureField(fields, 17)
+		f.FConType = featureField(fields, 18)
+		f.Type = featureField(fields, 19)
+		f.Kana = featureField(fields, 20)
+		f.KanaBase = featureField(fields, 21)
+		f.Form = featureField(fields, 22)
+		f.FormBase = featureField(fields, 23)
+		f.AType = featureField(fields, 24)
+		f.AConType = featureField(fields, 25)
+		f.AModType = featureField(fields, 26)
+		f.LID = featureField(fields, 27)
+		f.LemmaID = featureField(fields, 28)
+	}
+	return f
+}
+
+func detectUniDicLayout(n int) UniDicLayout {
+	switch {
+	case n >= 29:
+		return UniDicLayoutV22
+	case n >= 26:
+		return UniDicLayoutV21
+	}
+	return UniDicLayoutCompact
+}
+
+var reFeatureIndex = regexp.MustCompile(`%f\[(\d+)\]`)
+
+// DetectUniDicLayout detects the layout from the dicrc file of UniDic.
+// It finds the largest index of the feature fields in the node formats.
+func DetectUniDicLayout(dicrc string) (UniDicLayout, error) {
+	f, err := os.Open(dicrc)
+	if err != nil {
+		return UniDicLayoutAuto, err
+	}
+	defer f.Close()
+
+	max := -1
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		for _, m := range reFeatureIndex.FindAllStringSubmatch(s.Text(), -1) {
+			if i, err := strconv.Atoi(m[1]); err == nil && i > max {
+				max = i
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return UniDicLayoutAuto, err
+	}
+	if max < 0 {
+		return UniDicLayoutAuto, nil
+	}
+	return detectUniDicLayout(max + 1), nil
+}
+
+// UniDic returns the feature of the node decoded as UniDic.
+// The layout is detected by [DetectUniDicLayout] from dicrc of the dictionary of the tagger,
+// or from the number of the fields if dicrc is not available.
+//
+// The layout is detected when it is called for the first time by the nodes of the tagger,
+// and it is not updated by [Model.Swap].
+func (node Node) UniDic() UniDicFeature {
+	layout := UniDicLayoutAuto
+	if m := node.tagger(); m != nil {
+		layout = m.names.uniDicLayout(m)
+	}
+	return ParseUniDicFeatureLayout(node.Feature(), layout)
+}
+
+// UniDic returns the feature of the token decoded as UniDic.
+// The layout is detected from the number of the fields, because the token doesn't know the dictionary.
+// Use [ParseUniDicFeatureLayout] with the layout of [DetectUniDicLayout] to decode it with the layout of the dictionary.
+func (t Token) UniDic() UniDicFeature {
+	return ParseUniDicFeature(t.Feature)
+}
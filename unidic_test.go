@@ -0,0 +1,172 @@
+package mecab
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseUniDicFeature(t *testing.T) {
+	tests := []struct {
+		feature string
+		want    UniDicFeature
+	}{
+		{
+			// unidic-cwj 3.1.0
+			feature: "名詞,普通名詞,一般,*,*,*,セカイ,世界,世界,セカイ,世界,セカイ,漢,*,*,*,*,*,*,体,セカイ,セカイ,セカイ,セカイ,1,C1,*,5487559733944832,19962",
+			want: UniDicFeature{
+				Layout:   UniDicLayoutV22,
+				POS1:     "名詞",
+				POS2:     "普通名詞",
+				POS3:     "一般",
+				LForm:    "セカイ",
+				Lemma:    "世界",
+				Orth:     "世界",
+				Pron:     "セカイ",
+				OrthBase: "世界",
+				PronBase: "セカイ",
+				Goshu:    "漢",
+				Type:     "体",
+				Kana:     "セカイ",
+				KanaBase: "セカイ",
+				Form:     "セカイ",
+				FormBase: "セカイ",
+				AType:    "1",
+				AConType: "C1",
+				LID:      "5487559733944832",
+				LemmaID:  "19962",
+			},
+		},
+		{
+			// unidic-mecab 2.1.2
+			feature: "動詞,一般,*,*,五段-カ行,連用形-イ音便,カク,書く,書い,カイ,書く,カク,和,*,*,*,*,カイ,カク,カイ,カク,*,*,1,C2,*",
+			want: UniDicFeature{
+				Layout:   UniDicLayoutV21,
+				POS1:     "動詞",
+				POS2:     "一般",
+				CType:    "五段-カ行",
+				CForm:    "連用形-イ音便",
+				LForm:    "カク",
+				Lemma:    "書く",
+				Orth:     "書い",
+				Pron:     "カイ",
+				OrthBase: "書く",
+				PronBase: "カク",
+				Goshu:    "和",
+				Kana:     "カイ",
+				KanaBase: "カク",
+				Form:     "カイ",
+				FormBase: "カク",
+				AType:    "1",
+				AConType: "C2",
+			},
+		},
+		{
+			feature: "名詞,普通名詞,一般,*,*,*,セカイ,世界,世界,セカイ,世界,セカイ,漢,*,*,*,*",
+			want: UniDicFeature{
+				Layout:   UniDicLayoutCompact,
+				POS1:     "名詞",
+				POS2:     "普通名詞",
+				POS3:     "一般",
+				LForm:    "セカイ",
+				Lemma:    "世界",
+				Orth:     "世界",
+				Pron:     "セカイ",
+				OrthBase: "世界",
+				PronBase: "セカイ",
+				Goshu:    "漢",
+			},
+		},
+		{
+			// unknown word
+			feature: "名詞,普通名詞,一般,*,*,*",
+			want: UniDicFeature{
+				Layout: UniDicLayoutCompact,
+				POS1:   "名詞",
+				POS2:   "普通名詞",
+				POS3:   "一般",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got := ParseUniDicFeature(tt.feature)
+		if got != tt.want {
+			t.Errorf("%q: want %#v, got %#v", tt.feature, tt.want, got)
+		}
+	}
+}
+
+func TestParseUniDicFeatureLayout(t *testing.T) {
+	// the short row of unknown words is decoded with the layout.
+	got := ParseUniDicFeatureLayout("名詞,普通名詞,一般,*,*,*", UniDicLayoutV22)
+	want := UniDicFeature{
+		Layout: UniDicLayoutV22,
+		POS1:   "名詞",
+		POS2:   "普通名詞",
+		POS3:   "一般",
+	}
+	if got != want {
+		t.Errorf("want %#v, got %#v", want, got)
+	}
+}
+
+func TestDetectUniDicLayout(t *testing.T) {
+	dir := t.TempDir()
+	dicrc := filepath.Join(dir, "dicrc")
+	content := "; unidic\n" +
+		"node-format-unidic22 = %m\\t%f[9]\\t%f[6]\\t%f[7]\\t%F-[0,1,2,3]\\t%f[4]\\t%f[5]\\t%f[23]\\t%f[28]\\n\n"
+	if err := os.WriteFile(dicrc, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	layout, err := DetectUniDicLayout(dicrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layout != UniDicLayoutV22 {
+		t.Errorf("want %s, got %s", UniDicLayoutV22, layout)
+	}
+}
+
+func TestDetectUniDicLayout_fields(t *testing.T) {
+	tests := []struct {
+		n    int
+		want UniDicLayout
+	}{
+		{17, UniDicLayoutCompact},
+		{25, UniDicLayoutCompact},
+		{26, UniDicLayoutV21},
+		{28, UniDicLayoutV21},
+		{29, UniDicLayoutV22},
+	}
+	for _, tt := range tests {
+		if got := detectUniDicLayout(tt.n); got != tt.want {
+			t.Errorf("%d fields: want %s, got %s", tt.n, tt.want, got)
+		}
+	}
+}
+
+func TestNode_UniDic_dicrc(t *testing.T) {
+	args := buildPureGoDictionary(t, "UTF-8")
+	dicrc := "bos-feature = BOS/EOS,*,*\n" +
+		"node-format-unidic21 = %m\\t%f[0]\\t%f[25]\\n\n"
+	if err := os.WriteFile(filepath.Join(args["dicdir"], "dicrc"), []byte(dicrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tagger, err := New(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tagger.Destroy()
+
+	node, err := tagger.ParseToNode("東京")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the feature has only 3 fields, but the layout is detected from dicrc.
+	f := node.Next().UniDic()
+	if f.Layout != UniDicLayoutV21 || f.POS1 != "名詞" {
+		t.Errorf("unexpected feature: %#v", f)
+	}
+}
@@ -0,0 +1,203 @@
+package mecab
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Unmarshaler is the interface implemented by types that can unmarshal the feature fields by themselves.
+type Unmarshaler interface {
+	UnmarshalFeature(fields []string) error
+}
+
+// UnmarshalFeatureError describes a field that can't be decoded.
+type UnmarshalFeatureError struct {
+	// Field is the name of the struct field.
+	Field string
+
+	// Column is the index of the feature field.
+	Column int
+
+	// Value is the value of the feature field.
+	Value string
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *UnmarshalFeatureError) Error() string {
+	return fmt.Sprintf("mecab: cannot unmarshal column %d %q into field %s: %v", e.Column, e.Value, e.Field, e.Err)
+}
+
+func (e *UnmarshalFeatureError) Unwrap() error {
+	return e.Err
+}
+
+// ErrMissingColumn is the error that the feature doesn't have the column.
+var ErrMissingColumn = errors.New("missing column")
+
+var errInvalidUnmarshal = errors.New("mecab: UnmarshalFeature requires a non-nil pointer to a struct")
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// UnmarshalFeature decodes the feature string into v.
+//
+// If v implements [Unmarshaler], its UnmarshalFeature method is called with the fields.
+// Otherwise v must be a pointer to a struct, and the fields are decoded
+// into the struct fields with the "mecab" tag, which is the index of the column:
+//
+//	type Feature struct {
+//		POS      string `mecab:"0"`
+//		BaseForm string `mecab:"6"`
+//		Reading  string `mecab:"7,omitempty"`
+//		Cost     int    `mecab:"9,omitempty"`
+//	}
+//
+// The placeholder "*" is decoded as an empty string.
+// The struct fields with the omitempty option are left unchanged
+// if the column is missing or empty, otherwise it is an error that the column is missing.
+// The supported types are string, bool, integers, floats, [encoding.TextUnmarshaler],
+// and pointers to them.
+func UnmarshalFeature(feature string, v any) error {
+	fields := SplitFeature(feature)
+	if u, ok := v.(Unmarshaler); ok {
+		return u.UnmarshalFeature(fields)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errInvalidUnmarshal
+	}
+	rv = rv.Elem()
+
+	for _, f := range cachedFeatureFields(rv.Type()) {
+		value := ""
+		missing := f.column >= len(fields)
+		if !missing && fields[f.column] != "*" {
+			value = fields[f.column]
+		}
+		if value == "" && f.omitEmpty {
+			continue
+		}
+		if missing {
+			return &UnmarshalFeatureError{
+				Field:  f.name,
+				Column: f.column,
+				Err:    ErrMissingColumn,
+			}
+		}
+		if err := decodeFeatureField(rv.FieldByIndex(f.index), value); err != nil {
+			return &UnmarshalFeatureError{
+				Field:  f.name,
+				Column: f.column,
+				Value:  value,
+				Err:    err,
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalFeature decodes the feature of the node into v.
+// See [UnmarshalFeature] for details.
+func (node Node) UnmarshalFeature(v any) error {
+	return UnmarshalFeature(node.Feature(), v)
+}
+
+// UnmarshalFeature decodes the feature of the token into v.
+// See [UnmarshalFeature] for details.
+func (t Token) UnmarshalFeature(v any) error {
+	return UnmarshalFeature(t.Feature, v)
+}
+
+type taggedField struct {
+	name      string
+	index     []int
+	column    int
+	omitEmpty bool
+}
+
+var featureFieldsCache sync.Map // map[reflect.Type][]taggedField
+
+func cachedFeatureFields(t reflect.Type) []taggedField {
+	if f, ok := featureFieldsCache.Load(t); ok {
+		return f.([]taggedField)
+	}
+	f, _ := featureFieldsCache.LoadOrStore(t, typeFeatureFields(t))
+	return f.([]taggedField)
+}
+
+func typeFeatureFields(t reflect.Type) []taggedField {
+	var fields []taggedField
+	for _, sf := range reflect.VisibleFields(t) {
+		if !sf.IsExported() {
+			continue
+		}
+		tag, ok := sf.Tag.Lookup("mecab")
+		if !ok || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		column, err := strconv.Atoi(name)
+		if err != nil || column < 0 {
+			continue
+		}
+		fields = append(fields, taggedField{
+			name:      sf.Name,
+			index:     sf.Index,
+			column:    column,
+			omitEmpty: opts == "omitempty",
+		})
+	}
+	return fields
+}
+
+func decodeFeatureField(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package mecab
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testPOS string
+
+func (p *testPOS) UnmarshalText(text []byte) error {
+	*p = testPOS(strings.ToUpper(string(text)))
+	return nil
+}
+
+type testFeature struct {
+	POS      testPOS `mecab:"0"`
+	Sub      string  `mecab:"1"`
+	BaseForm string  `mecab:"6"`
+	Reading  *string `mecab:"7,omitempty"`
+	Cost     int     `mecab:"9,omitempty"`
+	Known    bool    `mecab:"10,omitempty"`
+	Ignored  string  `mecab:"-"`
+	NoTag    string
+}
+
+func TestUnmarshalFeature(t *testing.T) {
+	var f testFeature
+	err := UnmarshalFeature("noun,*,*,*,*,*,世界,セカイ,セカイ,-120,true", &f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.POS != "NOUN" || f.Sub != "" || f.BaseForm != "世界" || f.Reading == nil || *f.Reading != "セカイ" ||
+		f.Cost != -120 || !f.Known || f.Ignored != "" || f.NoTag != "" {
+		t.Errorf("unexpected result: %#v", f)
+	}
+}
+
+func TestUnmarshalFeature_omitempty(t *testing.T) {
+	var f testFeature
+	err := UnmarshalFeature("noun,*,*,*,*,*,*", &f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.POS != "NOUN" || f.Reading != nil || f.Cost != 0 || f.Known {
+		t.Errorf("unexpected result: %#v", f)
+	}
+}
+
+func TestUnmarshalFeature_error(t *testing.T) {
+	var f testFeature
+	err := UnmarshalFeature("noun,*,*", &f)
+	var uerr *UnmarshalFeatureError
+	if !errors.As(err, &uerr) || uerr.Field != "BaseForm" || !errors.Is(err, ErrMissingColumn) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err = UnmarshalFeature("noun,*,*,*,*,*,世界,セカイ,セカイ,foo", &f)
+	if !errors.As(err, &uerr) || uerr.Field != "Cost" || uerr.Value != "foo" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := UnmarshalFeature("noun", f); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+type testUnmarshaler struct {
+	fields []string
+}
+
+func (u *testUnmarshaler) UnmarshalFeature(fields []string) error {
+	u.fields = fields
+	return nil
+}
+
+func TestUnmarshalFeature_unmarshaler(t *testing.T) {
+	var u testUnmarshaler
+	if err := UnmarshalFeature("a,b,c", &u); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(u.fields, "|") != "a|b|c" {
+		t.Errorf("unexpected fields: %v", u.fields)
+	}
+}
@@ -0,0 +1,450 @@
+package mecab
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// UserEntry is an entry of a user dictionary.
+type UserEntry struct {
+	// Surface is the surface form of the word.
+	Surface string
+
+	// LeftID and RightID are the context IDs of the word.
+	LeftID  int
+	RightID int
+
+	// Cost is the cost of the word.
+	Cost int
+
+	// Features are the feature fields, e.g. the part-of-speech and the reading.
+	Features []string
+
+	// Auto means that the context IDs and the cost are assigned by [CostEstimator].
+	// LeftID, RightID and Cost are ignored if it is true.
+	Auto bool
+}
+
+// UserEntryError is an error of an entry of a user dictionary.
+type UserEntryError struct {
+	// Source is the name of the CSV source.
+	// It is empty if the entry is given as [UserEntry].
+	Source string
+
+	// Line is the line number in the source, or the index of the entry plus one.
+	Line int
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *UserEntryError) Error() string {
+	if e.Source != "" {
+		return fmt.Sprintf("%s:%d: %v", e.Source, e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *UserEntryError) Unwrap() error {
+	return e.Err
+}
+
+// UserDictionaryError is the error that a user dictionary has invalid entries.
+type UserDictionaryError struct {
+	Errors []*UserEntryError
+}
+
+func (e *UserDictionaryError) Error() string {
+	var buf strings.Builder
+	buf.WriteString("mecab: invalid user dictionary entries: ")
+	for i, err := range e.Errors {
+		if i > 0 {
+			buf.WriteString("; ")
+		}
+		buf.WriteString(err.Error())
+	}
+	return buf.String()
+}
+
+func (e *UserDictionaryError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Lines returns the line numbers of the invalid entries.
+func (e *UserDictionaryError) Lines() []int {
+	lines := make([]int, len(e.Errors))
+	for i, err := range e.Errors {
+		lines[i] = err.Line
+	}
+	return lines
+}
+
+// UserDictionaryCompiler compiles user dictionaries, as mecab-dict-index -u does.
+type UserDictionaryCompiler struct {
+	// Dicdir is the directory of the system dictionary.
+	// It must contain sys.dic, dicrc and matrix.bin or matrix.def.
+	// If it is empty, [DiscoverDictionary] finds it.
+	Dicdir string
+
+	// Charset is the charset of the user dictionary.
+	// If it is empty, the charset of the system dictionary is used.
+	Charset string
+
+	// Estimator assigns the context IDs and the costs of the entries with Auto.
+	Estimator *CostEstimator
+}
+
+// CompileUserDictionary compiles the entries into the user dictionary file output.
+// It is a shortcut of [UserDictionaryCompiler.Compile].
+func CompileUserDictionary(dicdir, charset, output string, entries []UserEntry) error {
+	c := &UserDictionaryCompiler{
+		Dicdir:  dicdir,
+		Charset: charset,
+	}
+	return c.Compile(output, entries)
+}
+
+// Compile compiles the entries into the user dictionary file output.
+// The entries are validated before compiling,
+// and the errors are reported as [*UserDictionaryError].
+func (c *UserDictionaryCompiler) Compile(output string, entries []UserEntry) error {
+	lines := make([]userDictionaryLine, len(entries))
+	for i, entry := range entries {
+		lines[i] = userDictionaryLine{
+			line:  i + 1,
+			entry: entry,
+		}
+	}
+	return c.compile(output, lines)
+}
+
+// CompileCSV compiles the CSV sources into the user dictionary file output.
+// The sources have the same format as the input of mecab-dict-index, encoded in UTF-8:
+//
+//	surface,left-id,right-id,cost,feature1,feature2,...
+//
+// If all of left-id, right-id and cost are empty, the entry is assigned by [UserDictionaryCompiler.Estimator].
+// If a source has the Name method, e.g. [*os.File], its name is used in the errors.
+func (c *UserDictionaryCompiler) CompileCSV(output string, sources ...io.Reader) error {
+	var lines []userDictionaryLine
+	var errs []*UserEntryError
+	for i, r := range sources {
+		name := fmt.Sprintf("source #%d", i+1)
+		if n, ok := r.(interface{ Name() string }); ok {
+			name = n.Name()
+		}
+		l, e, err := readUserDictionaryCSV(name, r)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, l...)
+		errs = append(errs, e...)
+	}
+	if len(errs) > 0 {
+		return &UserDictionaryError{Errors: errs}
+	}
+	return c.compile(output, lines)
+}
+
+type userDictionaryLine struct {
+	source string
+	line   int
+	entry  UserEntry
+}
+
+func readUserDictionaryCSV(name string, r io.Reader) ([]userDictionaryLine, []*UserEntryError, error) {
+	var lines []userDictionaryLine
+	var errs []*UserEntryError
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; s.Scan(); n++ {
+		text := strings.TrimSuffix(s.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		entry, err := parseUserEntry(text)
+		if err != nil {
+			errs = append(errs, &UserEntryError{Source: name, Line: n, Err: err})
+			continue
+		}
+		lines = append(lines, userDictionaryLine{
+			source: name,
+			line:   n,
+			entry:  entry,
+		})
+	}
+	if err := s.Err(); err != nil {
+		return nil, nil, err
+	}
+	return lines, errs, nil
+}
+
+func parseUserEntry(text string) (UserEntry, error) {
+	fields := SplitFeature(text)
+	if len(fields) < 5 {
+		return UserEntry{}, fmt.Errorf("want at least 5 columns, got %d", len(fields))
+	}
+	if fields[1] == "" && fields[2] == "" && fields[3] == "" {
+		return UserEntry{
+			Surface:  fields[0],
+			Features: fields[4:],
+			Auto:     true,
+		}, nil
+	}
+	var ids [3]int
+	for i, name := range []string{"left-id", "right-id", "cost"} {
+		v, err := strconv.Atoi(fields[i+1])
+		if err != nil {
+			return UserEntry{}, fmt.Errorf("invalid %s %q", name, fields[i+1])
+		}
+		ids[i] = v
+	}
+	return UserEntry{
+		Surface:  fields[0],
+		LeftID:   ids[0],
+		RightID:  ids[1],
+		Cost:     ids[2],
+		Features: fields[4:],
+	}, nil
+}
+
+// validateUserEntry validates the entry.
+// lsize and rsize are the number of the context IDs, or zero if they are unknown.
+func validateUserEntry(entry UserEntry, lsize, rsize int) error {
+	if err := validateUserWord(entry); err != nil {
+		return err
+	}
+	if entry.Auto {
+		return nil
+	}
+	if entry.LeftID < 0 || (lsize > 0 && entry.LeftID >= lsize) {
+		return fmt.Errorf("left-id %d is out of range [0, %d)", entry.LeftID, lsize)
+	}
+	if entry.RightID < 0 || (rsize > 0 && entry.RightID >= rsize) {
+		return fmt.Errorf("right-id %d is out of range [0, %d)", entry.RightID, rsize)
+	}
+	if entry.Cost < math.MinInt16 || entry.Cost > math.MaxInt16 {
+		return fmt.Errorf("cost %d is out of range [%d, %d]", entry.Cost, math.MinInt16, math.MaxInt16)
+	}
+	return nil
+}
+
+// validateUserWord validates the surface and the features of the entry.
+func validateUserWord(entry UserEntry) error {
+	if entry.Surface == "" {
+		return errors.New("empty surface")
+	}
+	if len(entry.Features) == 0 {
+		return errors.New("no features")
+	}
+	for _, s := range append([]string{entry.Surface}, entry.Features...) {
+		if !utf8.ValidString(s) {
+			return fmt.Errorf("invalid UTF-8 sequence in %q", s)
+		}
+		if strings.ContainsAny(s, "\x00\r\n") {
+			return fmt.Errorf("invalid character in %q", s)
+		}
+	}
+	return nil
+}
+
+func (c *UserDictionaryCompiler) compile(output string, lines []userDictionaryLine) error {
+	const op = "CompileUserDictionary"
+
+	dicdir := c.Dicdir
+	if dicdir == "" {
+		loc, err := DiscoverDictionary()
+		if err != nil {
+			return err
+		}
+		dicdir = loc.Dir
+	}
+	header, err := readSystemDictionaryHeader(filepath.Join(dicdir, "sys.dic"))
+	if err != nil {
+		return &Error{
+			Op:   op,
+			Path: dicdir,
+			Kind: KindDictionaryNotFound,
+			err:  "no such dictionary: " + dicdir,
+		}
+	}
+	charset := c.Charset
+	if charset == "" {
+		charset = header.charset
+	}
+
+	// MeCab terminates the process if it finds an invalid entry,
+	// so the entries must be validated here.
+	var errs []*UserEntryError
+	var auto []int
+	for i, l := range lines {
+		err := validateUserEntry(l.entry, header.lsize, header.rsize)
+		if err == nil && l.entry.Auto {
+			auto = append(auto, i)
+			if c.Estimator == nil {
+				err = errors.New("no estimator is given for the entry without the costs")
+			}
+		}
+		if err != nil {
+			errs = append(errs, &UserEntryError{Source: l.source, Line: l.line, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return &UserDictionaryError{Errors: errs}
+	}
+	if len(lines) == 0 {
+		return &Error{
+			Op:   op,
+			Kind: KindInvalidInput,
+			err:  "no entries",
+		}
+	}
+
+	if len(auto) > 0 {
+		entries := make([]UserEntry, len(auto))
+		for i, j := range auto {
+			entries[i] = lines[j].entry
+		}
+		estimated, err := c.Estimator.Estimate(entries)
+		if err != nil {
+			return err
+		}
+		lines = slices.Clone(lines)
+		for i, j := range auto {
+			lines[j].entry = estimated[i]
+		}
+	}
+
+	err = checkDictIndexFiles(op,
+		[]string{filepath.Join(dicdir, "dicrc")},
+		[]string{filepath.Join(dicdir, "matrix.bin"), filepath.Join(dicdir, "matrix.def")},
+	)
+	if err != nil {
+		return err
+	}
+
+	csv, err := writeUserDictionaryCSV(lines, nil)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(csv)
+
+	return dictIndex(op, output, []string{
+		"mecab-dict-index",
+		"--dicdir=" + dicdir,
+		"--userdic=" + output,
+		"--dictionary-charset=UTF-8",
+		"--charset=" + charset,
+		csv,
+	})
+}
+
+// checkDictIndexFiles checks that the files opened by mecab-dict-index are readable,
+// because mecab-dict-index terminates the process if it fails to open them.
+// Each element of files is the alternatives, and one of them must be readable.
+func checkDictIndexFiles(op string, files ...[]string) error {
+	for _, alternatives := range files {
+		var err error
+		for _, name := range alternatives {
+			var f *os.File
+			f, err = os.Open(name)
+			if err == nil {
+				f.Close()
+				break
+			}
+		}
+		if err != nil {
+			return &Error{
+				Op:   op,
+				Path: alternatives[0],
+				Kind: KindDictionaryNotFound,
+				err:  "cannot open " + strings.Join(alternatives, " or ") + ": " + err.Error(),
+			}
+		}
+	}
+	return nil
+}
+
+// writeUserDictionaryCSV writes the entries into a temporary CSV file and returns its name.
+// If enc is not nil, the entries are encoded by it.
+func writeUserDictionaryCSV(lines []userDictionaryLine, enc *codec) (string, error) {
+	f, err := os.CreateTemp("", "mecab-userdic-*.csv")
+	if err != nil {
+		return "", err
+	}
+	w := bufio.NewWriter(f)
+	for _, l := range lines {
+		fields := make([]string, 0, len(l.entry.Features)+4)
+		fields = append(fields,
+			l.entry.Surface,
+			strconv.Itoa(l.entry.LeftID),
+			strconv.Itoa(l.entry.RightID),
+			strconv.Itoa(l.entry.Cost),
+		)
+		fields = append(fields, l.entry.Features...)
+		line := JoinFeature(fields)
+		if enc != nil {
+			line, err = enc.encode("CompileUserDictionary", line)
+			if err != nil {
+				f.Close()
+				os.Remove(f.Name())
+				return "", &UserDictionaryError{Errors: []*UserEntryError{{Source: l.source, Line: l.line, Err: err}}}
+			}
+		}
+		w.WriteString(line)
+		w.WriteByte('\n')
+	}
+	err = w.Flush()
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// systemDictionaryHeader is a part of the header of sys.dic.
+type systemDictionaryHeader struct {
+	lsize   int
+	rsize   int
+	charset string
+}
+
+// readSystemDictionaryHeader reads the header of the dictionary file.
+// The header consists of ten uint32 values and the charset:
+// magic, version, type, lexsize, lsize, rsize, dsize, tsize, fsize, dummy and charset[32].
+func readSystemDictionaryHeader(name string) (systemDictionaryHeader, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return systemDictionaryHeader{}, err
+	}
+	defer f.Close()
+
+	var buf [72]byte
+	if _, err := io.ReadFull(f, buf[:]); err != nil {
+		return systemDictionaryHeader{}, err
+	}
+	charset, _, _ := bytes.Cut(buf[40:], []byte{0})
+	return systemDictionaryHeader{
+		lsize:   int(binary.LittleEndian.Uint32(buf[16:])),
+		rsize:   int(binary.LittleEndian.Uint32(buf[20:])),
+		charset: string(charset),
+	}, nil
+}
@@ -0,0 +1,50 @@
+//go:build cgo && !purego
+
+package mecab
+
+// #include <mecab.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"unsafe"
+)
+
+// dictIndexMu serializes mecab_dict_index, because it is not designed to be called concurrently.
+var dictIndexMu sync.Mutex
+
+// dictIndex calls mecab_dict_index with args, and checks that output is written.
+func dictIndex(op, output string, args []string) error {
+	cargs := make([]*C.char, len(args))
+	for i, arg := range args {
+		cargs[i] = C.CString(arg)
+		defer C.free(unsafe.Pointer(cargs[i]))
+	}
+
+	dictIndexMu.Lock()
+	defer dictIndexMu.Unlock()
+
+	// remove the old file to check that the new one is written.
+	if err := os.Remove(output); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	ret := C.mecab_dict_index(C.int(len(cargs)), (**C.char)(&cargs[0]))
+	if ret != 0 {
+		return &Error{
+			Op:   op,
+			Path: output,
+			err:  "mecab-dict-index failed",
+		}
+	}
+	if _, err := os.Stat(output); err != nil {
+		return &Error{
+			Op:   op,
+			Path: output,
+			err:  "the dictionary is not written",
+		}
+	}
+	return nil
+}
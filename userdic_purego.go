@@ -0,0 +1,12 @@
+//go:build !cgo || purego
+
+package mecab
+
+// dictIndex is not available without libmecab.
+func dictIndex(op, output string, args []string) error {
+	return &Error{
+		Op:   op,
+		Path: output,
+		err:  "compiling dictionaries requires libmecab, but it is built without cgo",
+	}
+}
@@ -0,0 +1,156 @@
+package mecab
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeDicdir creates a directory with the header of sys.dic.
+func fakeDicdir(t *testing.T, lsize, rsize int, charset string) string {
+	t.Helper()
+	dir := t.TempDir()
+	buf := make([]byte, 72)
+	binary.LittleEndian.PutUint32(buf[16:], uint32(lsize))
+	binary.LittleEndian.PutUint32(buf[20:], uint32(rsize))
+	copy(buf[40:], charset)
+	if err := os.WriteFile(filepath.Join(dir, "sys.dic"), buf, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestReadSystemDictionaryHeader(t *testing.T) {
+	dir := fakeDicdir(t, 1316, 1317, "utf-8")
+	header, err := readSystemDictionaryHeader(filepath.Join(dir, "sys.dic"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := systemDictionaryHeader{lsize: 1316, rsize: 1317, charset: "utf-8"}
+	if header != want {
+		t.Errorf("want %#v, got %#v", want, header)
+	}
+}
+
+func TestUserDictionaryCompiler_invalid(t *testing.T) {
+	c := &UserDictionaryCompiler{Dicdir: fakeDicdir(t, 10, 10, "utf-8")}
+	output := filepath.Join(t.TempDir(), "user.dic")
+	err := c.Compile(output, []UserEntry{
+		{Surface: "東京スカイツリー", LeftID: 1, RightID: 1, Cost: 100, Features: []string{"名詞"}},
+		{Surface: "", LeftID: 1, RightID: 1, Cost: 100, Features: []string{"名詞"}},
+		{Surface: "a", LeftID: 10, RightID: 1, Cost: 100, Features: []string{"名詞"}},
+		{Surface: "b", LeftID: 1, RightID: 1, Cost: 40000, Features: []string{"名詞"}},
+		{Surface: "c", LeftID: 1, RightID: 1, Cost: 100},
+	})
+
+	var dicErr *UserDictionaryError
+	if !errors.As(err, &dicErr) {
+		t.Fatalf("want UserDictionaryError, got %v", err)
+	}
+	if want := []int{2, 3, 4, 5}; !slices.Equal(dicErr.Lines(), want) {
+		t.Errorf("want lines %v, got %v", want, dicErr.Lines())
+	}
+	if _, err := os.Stat(output); err == nil {
+		t.Error("want no output")
+	}
+}
+
+func TestUserDictionaryCompiler_CompileCSV_invalid(t *testing.T) {
+	c := &UserDictionaryCompiler{Dicdir: fakeDicdir(t, 10, 10, "utf-8")}
+	output := filepath.Join(t.TempDir(), "user.dic")
+	csv := "東京スカイツリー,1,1,100,名詞,固有名詞\n" +
+		"\n" +
+		"a,1,1,名詞\n" +
+		"b,x,1,100,名詞\n" +
+		`"c,d",1,1,100,名詞` + "\n"
+	err := c.CompileCSV(output, strings.NewReader(csv))
+
+	var dicErr *UserDictionaryError
+	if !errors.As(err, &dicErr) {
+		t.Fatalf("want UserDictionaryError, got %v", err)
+	}
+	if want := []int{3, 4}; !slices.Equal(dicErr.Lines(), want) {
+		t.Errorf("want lines %v, got %v", want, dicErr.Lines())
+	}
+	if !strings.Contains(err.Error(), "source #1:4: invalid left-id") {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestParseUserEntry(t *testing.T) {
+	entry, err := parseUserEntry(`"1,000円",5,6,-10,名詞,"a""b"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Surface != "1,000円" || entry.LeftID != 5 || entry.RightID != 6 || entry.Cost != -10 ||
+		!slices.Equal(entry.Features, []string{"名詞", `a"b`}) {
+		t.Errorf("unexpected entry: %#v", entry)
+	}
+}
+
+func TestUserDictionaryCompiler_noMatrix(t *testing.T) {
+	dicdir := fakeDicdir(t, 10, 10, "utf-8")
+	if err := os.WriteFile(filepath.Join(dicdir, "dicrc"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := &UserDictionaryCompiler{Dicdir: dicdir}
+	output := filepath.Join(t.TempDir(), "user.dic")
+	err := c.Compile(output, []UserEntry{
+		{Surface: "東京スカイツリー", LeftID: 1, RightID: 1, Cost: 100, Features: []string{"名詞"}},
+	})
+	if !errors.Is(err, ErrDictionaryNotFound) || !strings.Contains(err.Error(), "matrix") {
+		t.Errorf("want ErrDictionaryNotFound for the matrix, got %v", err)
+	}
+}
+
+func TestCompileUserDictionary(t *testing.T) {
+	requireLibMeCab(t)
+	loc, err := DiscoverDictionary()
+	if err != nil {
+		t.Skip("no dictionary is found")
+	}
+
+	output := filepath.Join(t.TempDir(), "user.dic")
+	err = CompileUserDictionary(loc.Dir, "", output, []UserEntry{
+		{Surface: "東京スカイツリー", LeftID: 1, RightID: 1, Cost: 100, Features: []string{"名詞", "固有名詞", "*", "*", "*", "*", "東京スカイツリー"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	model, err := NewModelFromDicdir(loc.Dir, map[string]string{"userdic": output})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer model.Destroy()
+
+	info := model.DictionaryInfo()
+	if !slices.ContainsFunc(info, func(info DictionaryInfo) bool { return info.Type == UserDictionary }) {
+		t.Errorf("want user dictionary, got %v", info)
+	}
+}
+
+// buildTestDictionary compiles the seed dictionary in testdata/seed into a temporary directory.
+func buildTestDictionary(t *testing.T) string {
+	t.Helper()
+	requireLibMeCab(t)
+	dir := t.TempDir()
+	if err := os.CopyFS(dir, os.DirFS("testdata/seed")); err != nil {
+		t.Fatal(err)
+	}
+	err := dictIndex("CompileDictionary", filepath.Join(dir, "sys.dic"), []string{
+		"mecab-dict-index",
+		"--dicdir=" + dir,
+		"--outdir=" + dir,
+		"--dictionary-charset=UTF-8",
+		"--charset=UTF-8",
+	})
+	if err != nil {
+		t.Fatalf("failed to compile the test dictionary: %v", err)
+	}
+	return dir
+}
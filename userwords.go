@@ -0,0 +1,73 @@
+package mecab
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WithUserWords compiles the entries into a temporary user dictionary,
+// and returns a new model that uses it after the dictionaries of m.
+// The new model is ready for [Model.Swap], and the temporary dictionary is removed
+// when the new model, or the model that it is swapped into, is destroyed.
+// The entries must have the context IDs and the costs. Use [CostEstimator] to assign them.
+func (m Model) WithUserWords(entries []UserEntry) (Model, error) {
+	const op = "WithUserWords"
+
+	var dicdir, charset string
+	var userdics []string
+	for _, info := range m.DictionaryInfo() {
+		switch info.Type {
+		case SystemDictionary:
+			dicdir = filepath.Dir(info.Filename)
+			charset = info.Charset
+		case UserDictionary:
+			userdics = append(userdics, info.Filename)
+		}
+	}
+	if dicdir == "" {
+		return Model{}, &Error{
+			Op:   op,
+			Kind: KindDictionaryNotFound,
+			err:  "the system dictionary is not found",
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "mecab-userdic-*")
+	if err != nil {
+		return Model{}, err
+	}
+	userdic := filepath.Join(dir, "user.dic")
+	if err := CompileUserDictionary(dicdir, charset, userdic, entries); err != nil {
+		os.RemoveAll(dir)
+		return Model{}, err
+	}
+
+	args := make(map[string]string, len(m.m.args)+1)
+	for k, v := range m.m.args {
+		args[k] = v
+	}
+	args["userdic"] = strings.Join(append(userdics, userdic), ",")
+
+	var ret Model
+	if rc, ok := args["rcfile"]; ok && fileExists(rc) {
+		args["dicdir"] = dicdir
+		ret, err = NewModel(args)
+	} else {
+		// the rcfile may be generated by NewModelFromDicdir, and it is already removed.
+		delete(args, "rcfile")
+		ret, err = NewModelFromDicdir(dicdir, args)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return Model{}, err
+	}
+	ret.m.tempDirs = append(ret.m.tempDirs, dir)
+	return ret, nil
+}
+
+// fileExists reports whether the file exists and is accessible.
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
@@ -0,0 +1,99 @@
+package mecab
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestModel_WithUserWords(t *testing.T) {
+	requireLibMeCab(t)
+	model, err := NewModel(rcfile(map[string]string{
+		"output-format-type": "wakati",
+	}))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer model.Destroy()
+
+	// use the context IDs and the cost of an existing noun.
+	mecab, err := model.NewMeCab()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer mecab.Destroy()
+	node, err := mecab.ParseToNode("世界")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	noun := node.Next()
+
+	model2, err := model.WithUserWords([]UserEntry{{
+		Surface:  "こんにちは世界",
+		LeftID:   noun.LCAttr(),
+		RightID:  noun.RCAttr(),
+		Cost:     -10000,
+		Features: SplitFeature(noun.Feature()),
+	}})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	tempDirs := model2.m.tempDirs
+	if len(tempDirs) != 1 {
+		t.Fatalf("want 1 temporary directory, got %v", tempDirs)
+	}
+
+	if err := model.Swap(model2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	// model2 is consumed by Swap, and model uses its dictionary now.
+	model2.Destroy()
+	if _, err := os.Stat(tempDirs[0]); err != nil {
+		t.Errorf("want %s to survive the swap, got %v", tempDirs[0], err)
+	}
+	// the next WithUserWords rebuilds model from the arguments of model2.
+	if !strings.Contains(model.m.args["userdic"], tempDirs[0]) {
+		t.Errorf("want the user dictionary in %s, got %q", tempDirs[0], model.m.args["userdic"])
+	}
+	defer func() {
+		model.Destroy()
+		if _, err := os.Stat(tempDirs[0]); !os.IsNotExist(err) {
+			t.Errorf("want %s to be removed, got %v", tempDirs[0], err)
+		}
+	}()
+
+	mecab2, err := model.NewMeCab()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer mecab2.Destroy()
+	result, err := mecab2.Parse("こんにちは世界")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	if strings.TrimSpace(result) != "こんにちは世界" {
+		t.Errorf("want `こんにちは世界`, but `%s`", result)
+	}
+}
+
+func TestFileExists(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(name, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !fileExists(name) {
+		t.Errorf("want %s to exist", name)
+	}
+	// stat fails with ENOTDIR, which is not ErrNotExist.
+	if fileExists(filepath.Join(name, "child")) {
+		t.Errorf("want %s not to exist", filepath.Join(name, "child"))
+	}
+}